@@ -27,15 +27,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"regexp"
 	"strconv"
-	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/comcast/fishymetrics/buildinfo"
 	"github.com/comcast/fishymetrics/common"
 	"github.com/comcast/fishymetrics/config"
+	"github.com/comcast/fishymetrics/events"
+	"github.com/comcast/fishymetrics/exporter"
 	"github.com/comcast/fishymetrics/http/handlers"
 	"github.com/comcast/fishymetrics/logger"
 	"github.com/comcast/fishymetrics/middleware/logging"
@@ -43,6 +44,7 @@ import (
 	fishy_vault "github.com/comcast/fishymetrics/vault"
 	"go.uber.org/zap"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -53,30 +55,98 @@ const (
 )
 
 var (
-	a                  = kingpin.New(app, "redfish api exporter with all the bells and whistles")
-	username           = a.Flag("user", "BMC static username").Default("").Envar("BMC_USERNAME").String()
-	password           = a.Flag("password", "BMC static password").Default("").Envar("BMC_PASSWORD").String()
-	bmcTimeout         = a.Flag("timeout", "BMC scrape timeout").Default("15s").Envar("BMC_TIMEOUT").Duration()
-	bmcScheme          = a.Flag("scheme", "BMC Scheme to use").Default("https").Envar("BMC_SCHEME").String()
-	insecureSkipVerify = a.Flag("insecure-skip-verify", "Skip TLS verification").Default("false").Envar("INSECURE_SKIP_VERIFY").Bool()
-	logLevel           = a.Flag("log.level", "log level verbosity").PlaceHolder("[debug|info|warn|error]").Default("info").Envar("LOG_LEVEL").String()
-	logMethod          = a.Flag("log.method", "alternative method for logging in addition to stdout").PlaceHolder("[file|vector]").Default("").Envar("LOG_METHOD").String()
-	logFilePath        = a.Flag("log.file-path", "directory path where log files are written if log-method is file").Default("/var/log/fishymetrics").Envar("LOG_FILE_PATH").String()
-	logFileMaxSize     = a.Flag("log.file-max-size", "max file size in megabytes if log-method is file").Default("256").Envar("LOG_FILE_MAX_SIZE").String()
-	logFileMaxBackups  = a.Flag("log.file-max-backups", "max file backups before they are rotated if log-method is file").Default("1").Envar("LOG_FILE_MAX_BACKUPS").String()
-	logFileMaxAge      = a.Flag("log.file-max-age", "max file age in days before they are rotated if log-method is file").Default("1").Envar("LOG_FILE_MAX_AGE").String()
-	vectorEndpoint     = a.Flag("vector.endpoint", "vector endpoint to send structured json logs to").Default("http://0.0.0.0:4444").Envar("VECTOR_ENDPOINT").String()
-	exporterPort       = a.Flag("port", "exporter port").Default("10023").Envar("EXPORTER_PORT").String()
-	vaultAddr          = a.Flag("vault.addr", "Vault instance address to get chassis credentials from").Default("https://vault.com").Envar("VAULT_ADDRESS").String()
-	vaultRoleId        = a.Flag("vault.role-id", "Vault Role ID for AppRole").Default("").Envar("VAULT_ROLE_ID").String()
-	vaultSecretId      = a.Flag("vault.secret-id", "Vault Secret ID for AppRole").Default("").Envar("VAULT_SECRET_ID").String()
-	driveModExclude    = a.Flag("collector.drives.modules-exclude", "regex of drive module(s) to exclude from the scrape").Default("").Envar("COLLECTOR_DRIVES_MODULE_EXCLUDE").String()
-	firmwareModExclude = a.Flag("collector.firmware.modules-exclude", "regex of firmware module(s) to exclude from the scrape").Default("").Envar("COLLECTOR_FIRMWARE_MODULE_EXCLUDE").String()
-	urlExtraParams     = a.Flag("url.extra-params", `extra parameter(s) to parse from the URL. --url.extra-params="param1:alias1,param2:alias2"`).Default("").Envar("URL_EXTRA_PARAMS").String()
-	disable404Retry    = a.Flag("disable-404-retry", "Skip retrying on HTTP 404 (no 404 retry loop).").Default("false").Envar("FISHYMETRICS_DISABLE_404_RETRY").Bool()
-	credentialsScript  = a.Flag("credentials-script", "script to run to get the BMC credentials").Default("").Envar("BMC_CREDENTIALS_SCRIPT").String()
-	_                  = common.CredentialProf(a.Flag("credentials.profiles",
-		`profile(s) with all necessary parameters to obtain BMC credential from secrets backend, i.e.
+	a, f = newApp()
+
+	log *zap.Logger
+
+	vault              *fishy_vault.Vault
+	subscriber         *events.Subscriber
+	poller             *handlers.Poller
+	current            *handlers.CurrentConfig
+	extraParamsAliases = make(map[string]string)
+)
+
+// flags are the argument flags of the app, reload parses them again into a new app
+type flags struct {
+	username           *string
+	password           *string
+	bmcTimeout         *time.Duration
+	bmcScheme          *string
+	insecureSkipVerify *bool
+	logLevel           *string
+	logMethod          *string
+	logFilePath        *string
+	logFileMaxSize     *string
+	logFileMaxBackups  *string
+	logFileMaxAge      *string
+	vectorEndpoint     *string
+	exporterPort       *string
+	vaultAddr          *string
+	vaultRoleId        *string
+	vaultSecretId      *string
+	driveModExclude    *string
+	firmwareModExclude *string
+	urlExtraParams     *string
+	disable404Retry    *bool
+	credentialsScript  *string
+	eventsDestination  *string
+	eventsSSE          *bool
+	eventsTTL          *time.Duration
+	refreshIntervals   *string
+	cacheMaxAge        *string
+	staleWindow        *time.Duration
+	pollerTargetsFile  *string
+	pollerInterval     *time.Duration
+	maxConcurrent      *int
+	maxPerTarget       *int
+	maxQueue           *int
+	queueTimeout       *time.Duration
+	configFile         *string
+	credProfiles       *common.CredentialProfilesFlag
+}
+
+// newApp returns the kingpin app of the exporter and its flags
+func newApp() (*kingpin.Application, *flags) {
+	a := kingpin.New(app, "redfish api exporter with all the bells and whistles")
+	a.HelpFlag.Short('h')
+
+	return a, &flags{
+		username:           a.Flag("user", "BMC static username").Default("").Envar("BMC_USERNAME").String(),
+		password:           a.Flag("password", "BMC static password").Default("").Envar("BMC_PASSWORD").String(),
+		bmcTimeout:         a.Flag("timeout", "BMC scrape timeout").Default("15s").Envar("BMC_TIMEOUT").Duration(),
+		bmcScheme:          a.Flag("scheme", "BMC Scheme to use").Default("https").Envar("BMC_SCHEME").String(),
+		insecureSkipVerify: a.Flag("insecure-skip-verify", "Skip TLS verification").Default("false").Envar("INSECURE_SKIP_VERIFY").Bool(),
+		logLevel:           a.Flag("log.level", "log level verbosity").PlaceHolder("[debug|info|warn|error]").Default("info").Envar("LOG_LEVEL").String(),
+		logMethod:          a.Flag("log.method", "alternative method for logging in addition to stdout").PlaceHolder("[file|vector]").Default("").Envar("LOG_METHOD").String(),
+		logFilePath:        a.Flag("log.file-path", "directory path where log files are written if log-method is file").Default("/var/log/fishymetrics").Envar("LOG_FILE_PATH").String(),
+		logFileMaxSize:     a.Flag("log.file-max-size", "max file size in megabytes if log-method is file").Default("256").Envar("LOG_FILE_MAX_SIZE").String(),
+		logFileMaxBackups:  a.Flag("log.file-max-backups", "max file backups before they are rotated if log-method is file").Default("1").Envar("LOG_FILE_MAX_BACKUPS").String(),
+		logFileMaxAge:      a.Flag("log.file-max-age", "max file age in days before they are rotated if log-method is file").Default("1").Envar("LOG_FILE_MAX_AGE").String(),
+		vectorEndpoint:     a.Flag("vector.endpoint", "vector endpoint to send structured json logs to").Default("http://0.0.0.0:4444").Envar("VECTOR_ENDPOINT").String(),
+		exporterPort:       a.Flag("port", "exporter port").Default("10023").Envar("EXPORTER_PORT").String(),
+		vaultAddr:          a.Flag("vault.addr", "Vault instance address to get chassis credentials from").Default("https://vault.com").Envar("VAULT_ADDRESS").String(),
+		vaultRoleId:        a.Flag("vault.role-id", "Vault Role ID for AppRole").Default("").Envar("VAULT_ROLE_ID").String(),
+		vaultSecretId:      a.Flag("vault.secret-id", "Vault Secret ID for AppRole").Default("").Envar("VAULT_SECRET_ID").String(),
+		driveModExclude:    a.Flag("collector.drives.modules-exclude", "regex of drive module(s) to exclude from the scrape").Default("").Envar("COLLECTOR_DRIVES_MODULE_EXCLUDE").String(),
+		firmwareModExclude: a.Flag("collector.firmware.modules-exclude", "regex of firmware module(s) to exclude from the scrape").Default("").Envar("COLLECTOR_FIRMWARE_MODULE_EXCLUDE").String(),
+		urlExtraParams:     a.Flag("url.extra-params", `extra parameter(s) to parse from the URL. --url.extra-params="param1:alias1,param2:alias2"`).Default("").Envar("URL_EXTRA_PARAMS").String(),
+		disable404Retry:    a.Flag("disable-404-retry", "Skip retrying on HTTP 404 (no 404 retry loop).").Default("false").Envar("FISHYMETRICS_DISABLE_404_RETRY").Bool(),
+		credentialsScript:  a.Flag("credentials-script", "script to run to get the BMC credentials").Default("").Envar("BMC_CREDENTIALS_SCRIPT").String(),
+		eventsDestination:  a.Flag("events.destination", "url of the /events route as reachable from the BMCs, subscribes to the EventService of every scraped target when set").Default("").Envar("EVENTS_DESTINATION").String(),
+		eventsSSE:          a.Flag("events.sse", "stream the events of the scraped targets that advertise an EventService ServerSentEventUri instead of subscribing events.destination").Default("false").Envar("EVENTS_SSE").Bool(),
+		eventsTTL:          a.Flag("events.subscription-ttl", "how long an EventService subscription is used before it is recreated, subscriptions of targets not scraped within this time are deleted").Default("1h").Envar("EVENTS_SUBSCRIPTION_TTL").Duration(),
+		refreshIntervals:   a.Flag("collector.refresh-intervals", `how long the resources of a component are served from the last successful result before they are fetched again. --collector.refresh-intervals="firmware=6h,memory=1h"`).Default("").Envar("COLLECTOR_REFRESH_INTERVALS").String(),
+		cacheMaxAge:        a.Flag("cache.max-age", `deprecated, use collector.refresh-intervals. Refresh interval of the inventory components without one in collector.refresh-intervals. --cache.max-age="firmware=6h,memory=1h"`).Default("").Envar("CACHE_MAX_AGE").String(),
+		staleWindow:        a.Flag("scrape.stale-window", "how long the metrics of the last successful scrape of a target are served with redfish_up 0 when its scrapes fail, disabled when 0").Default("0s").Envar("SCRAPE_STALE_WINDOW").Duration(),
+		pollerTargetsFile:  a.Flag("poller.targets-file", "yaml file of the targets to scrape in the background, /scrape of a polled target is served from its last poll").Default("").Envar("POLLER_TARGETS_FILE").String(),
+		pollerInterval:     a.Flag("poller.interval", "interval of the polled targets that don't set one").Default("1m").Envar("POLLER_INTERVAL").Duration(),
+		maxConcurrent:      a.Flag("scrape.max-concurrent", "max number of scrapes running at once, the others wait in a queue, unlimited when 0").Default("0").Envar("SCRAPE_MAX_CONCURRENT").Int(),
+		maxPerTarget:       a.Flag("scrape.max-per-target", "max number of running and queued scrapes of a target, the others are rejected with a 429, unlimited when 0").Default("0").Envar("SCRAPE_MAX_PER_TARGET").Int(),
+		maxQueue:           a.Flag("scrape.max-queue", "max number of scrapes waiting for scrape.max-concurrent, the others are rejected with a 503").Default("100").Envar("SCRAPE_MAX_QUEUE").Int(),
+		queueTimeout:       a.Flag("scrape.queue-timeout", "how long a scrape waits in the queue before it is rejected with a 503").Default("10s").Envar("SCRAPE_QUEUE_TIMEOUT").Duration(),
+		configFile:         a.Flag("config.file", "yaml file of the modules picked by the module query parameter of the scrape requests").Default("").Envar("CONFIG_FILE").String(),
+		credProfiles: common.CredentialProf(a.Flag("credentials.profiles",
+			`profile(s) with all necessary parameters to obtain BMC credential from secrets backend, i.e.
   --credentials.profiles="
     profiles:
       - name: profile1
@@ -86,21 +156,17 @@ var (
         passwordField: "password"
       ...
   "
---credentials.profiles='{"profiles":[{"name":"profile1","mountPath":"kv2","path":"path/to/secret","userField":"user","passwordField":"password"},...]}'`))
-
-	log *zap.Logger
-
-	vault              *fishy_vault.Vault
-	excludes           = make(map[string]interface{})
-	urlExtraParamsMap  = make(map[string]string)
-	extraParamsAliases = make(map[string]string)
-)
+--credentials.profiles='{"profiles":[{"name":"profile1","mountPath":"kv2","path":"path/to/secret","userField":"user","passwordField":"password"},...]}'`)),
+	}
+}
 
 var wg = sync.WaitGroup{}
 
 func main() {
 	ctx := context.Background()
 	doneRenew := make(chan bool, 1)
+	doneEvents := make(chan bool, 1)
+	donePoller := make(chan bool, 1)
 	tokenLifecycle := make(chan bool, 1)
 
 	hostname, err := os.Hostname()
@@ -112,126 +178,107 @@ func main() {
 	// Unsolicited response received on idle HTTP channel starting with "\n"; err=<nil>
 	logg.SetOutput(io.Discard)
 
-	a.HelpFlag.Short('h')
-
 	_, err = a.Parse(os.Args[1:])
 	if err != nil {
 		panic(fmt.Errorf("error parsing argument flags - %s", err.Error()))
 	}
 
-	// populate excludes map
-	if *driveModExclude != "" {
-		driveModPattern := regexp.MustCompile(*driveModExclude)
-		excludes["drive"] = driveModPattern
-	}
-
-	if *firmwareModExclude != "" {
-		firmwareModPattern := regexp.MustCompile(*firmwareModExclude)
-		excludes["firmware"] = firmwareModPattern
-	}
-
 	// validate logFilePath exists and is a directory
-	if *logMethod == "file" {
-		fd, err := os.Stat(*logFilePath)
+	if *f.logMethod == "file" {
+		fd, err := os.Stat(*f.logFilePath)
 		if os.IsNotExist(err) {
 			panic(err)
 		}
 		if !fd.IsDir() {
-			panic(fmt.Errorf("%s is not a directory", *logFilePath))
+			panic(fmt.Errorf("%s is not a directory", *f.logFilePath))
 		}
 	}
 
-	logfileMaxSize, err := strconv.Atoi(*logFileMaxSize)
+	logfileMaxSize, err := strconv.Atoi(*f.logFileMaxSize)
 	if err != nil {
 		panic(fmt.Errorf("error converting arg --log.file-max-size to int - %s", err.Error()))
 	}
 
-	logfileMaxBackups, err := strconv.Atoi(*logFileMaxBackups)
+	logfileMaxBackups, err := strconv.Atoi(*f.logFileMaxBackups)
 	if err != nil {
 		panic(fmt.Errorf("error converting arg --log.file-max-backups to int - %s", err.Error()))
 	}
 
-	logfileMaxAge, err := strconv.Atoi(*logFileMaxAge)
+	logfileMaxAge, err := strconv.Atoi(*f.logFileMaxAge)
 	if err != nil {
 		panic(fmt.Errorf("error converting arg --log.file-max-age to int - %s", err.Error()))
 	}
 
-	c := &config.Config{
-		BMCScheme:       *bmcScheme,
-		BMCTimeout:      *bmcTimeout,
-		SSLVerify:       *insecureSkipVerify,
-		User:            *username,
-		Pass:            *password,
-		Disable404Retry: *disable404Retry,
+	settings, err := loadSettings(f)
+	if err != nil {
+		panic(err)
 	}
 
-	config.NewConfig(c)
+	config.NewConfig(settings.config)
+	common.ChassisCreds.SetProfiles(&settings.credProfiles)
+	common.TargetTLS.Set(settings.targetTLS)
+	exporter.MetricMappings.Set(settings.metricMappings)
 
 	// init logger config
 	logConfig := logger.LoggerConfig{
-		LogLevel:  *logLevel,
-		LogMethod: *logMethod,
+		LogLevel:  *f.logLevel,
+		LogMethod: *f.logMethod,
 		LogFile: logger.LogFile{
-			Path:       *logFilePath,
+			Path:       *f.logFilePath,
 			MaxSize:    logfileMaxSize,
 			MaxBackups: logfileMaxBackups,
 			MaxAge:     logfileMaxAge,
 		},
-		VectorEndpoint: *vectorEndpoint,
+		VectorEndpoint: *f.vectorEndpoint,
 	}
 
 	err = logger.Initialize(app, hostname, logConfig)
 	if err != nil {
 		panic(fmt.Errorf("error initializing logger - log_method=%s vector_endpoint=%s log_file_path=%s log_file_max_size=%d log_file_max_backups=%d log_file_max_age=%d - err=%s",
-			*logMethod, *vectorEndpoint, *logFilePath, logfileMaxSize, logfileMaxBackups, logfileMaxAge, err.Error()))
+			*f.logMethod, *f.vectorEndpoint, *f.logFilePath, logfileMaxSize, logfileMaxBackups, logfileMaxAge, err.Error()))
 	}
 
 	log = zap.L()
 	defer logger.Flush()
 
-	if *logMethod == "vector" {
-		log.Info("successfully initialized logger", zap.String("log_method", *logMethod),
-			zap.String("vector_endpoint", *vectorEndpoint))
-	} else if *logMethod == "file" {
-		log.Info("successfully initialized logger", zap.String("log_method", *logMethod),
-			zap.String("log_file_path", *logFilePath),
+	if *f.cacheMaxAge != "" {
+		log.Warn("--cache.max-age is deprecated, use --collector.refresh-intervals", zap.String("cache_max_age", *f.cacheMaxAge))
+	}
+
+	if *f.logMethod == "vector" {
+		log.Info("successfully initialized logger", zap.String("log_method", *f.logMethod),
+			zap.String("vector_endpoint", *f.vectorEndpoint))
+	} else if *f.logMethod == "file" {
+		log.Info("successfully initialized logger", zap.String("log_method", *f.logMethod),
+			zap.String("log_file_path", *f.logFilePath),
 			zap.Int("log_file_max_size", logfileMaxSize),
 			zap.Int("log_file_max_backups", logfileMaxBackups),
 			zap.Int("log_file_max_age", logfileMaxAge))
 	}
 
-	// populate urlExtraParamsMap if url extra params are passed
-	if *urlExtraParams != "" {
-		// --url.extra.params="short_hostname:shortname,param2:alias1,param3:alias2"
-		for _, param := range strings.Split(*urlExtraParams, ",") {
-			kv := strings.Split(param, ":")
-			if len(kv) != 2 {
-				log.Error("error parsing url extra params", zap.Error(err), zap.String("url_extra_params", *urlExtraParams))
-				return
-			}
-			urlExtraParamsMap[kv[0]] = kv[1]
-		}
+	if len(settings.urlExtraParamsMap) > 0 {
+		log.Info("parsed url extra params", zap.Any("url_extra_params", settings.urlExtraParamsMap))
 	}
 
-	if len(urlExtraParamsMap) > 0 {
-		log.Info("parsed url extra params", zap.Any("url_extra_params", urlExtraParamsMap))
+	if *f.configFile != "" {
+		log.Info("loaded modules from config file", zap.String("config_file", *f.configFile), zap.Int("modules", len(settings.modules)))
 	}
 
 	// configure vault client if vaultRoleId & vaultSecretId are set
-	if *vaultRoleId != "" && *vaultSecretId != "" {
+	if *f.vaultRoleId != "" && *f.vaultSecretId != "" {
 		var err error
 		vault, err = fishy_vault.NewVaultAppRoleClient(
 			ctx,
 			fishy_vault.Parameters{
-				Address:         *vaultAddr,
-				ApproleRoleID:   *vaultRoleId,
-				ApproleSecretID: *vaultSecretId,
+				Address:         *f.vaultAddr,
+				ApproleRoleID:   *f.vaultRoleId,
+				ApproleSecretID: *f.vaultSecretId,
 			},
 		)
 		if err != nil {
 			log.Error("failed initializing vault client", zap.Error(err),
-				zap.String("vault_address", *vaultAddr),
-				zap.String("vault_role_id", *vaultRoleId))
+				zap.String("vault_address", *f.vaultAddr),
+				zap.String("vault_role_id", *f.vaultRoleId))
 		} else {
 			// we add this here so we can update credentials once we detect they are rotated
 			common.ChassisCreds.Vault = vault
@@ -242,15 +289,52 @@ func main() {
 		}
 	}
 
+	// start go routine to clean up the EventService subscriptions and streams if events are enabled
+	if *f.eventsDestination != "" || *f.eventsSSE {
+		subscriber = events.NewSubscriber(*f.eventsDestination, *f.eventsTTL, *f.eventsSSE)
+
+		wg.Add(1)
+		go subscriber.Run(doneEvents, &wg)
+
+		log.Info("subscribing to scraped targets event service", zap.String("events_destination", *f.eventsDestination),
+			zap.Bool("events_sse", *f.eventsSSE), zap.Duration("events_subscription_ttl", *f.eventsTTL))
+	}
+
 	// Create scrape handler configuration
-	scrapeConfig := &handlers.ScrapeConfig{
+	scrapeConfig := handlers.ScrapeConfig{
 		Vault:              vault,
-		CredentialsScript:  *credentialsScript,
-		Excludes:           excludes,
-		URLExtraParamsMap:  urlExtraParamsMap,
+		CredentialsScript:  *f.credentialsScript,
+		Excludes:           settings.excludes,
+		URLExtraParamsMap:  settings.urlExtraParamsMap,
 		ExtraParamsAliases: extraParamsAliases,
+		Events:             subscriber,
+		Coalescer:          handlers.NewCoalescer(prometheus.DefaultRegisterer),
+		Modules:            settings.modules,
+	}
+
+	if *f.staleWindow > 0 {
+		scrapeConfig.Stale = handlers.NewStaleCache(*f.staleWindow)
+	}
+
+	if *f.maxConcurrent > 0 || *f.maxPerTarget > 0 {
+		scrapeConfig.Limiter = handlers.NewLimiter(*f.maxConcurrent, *f.maxPerTarget, *f.maxQueue, *f.queueTimeout, prometheus.DefaultRegisterer)
+	}
+
+	// start go routine to scrape the targets in the background if a targets file is configured
+	if *f.pollerTargetsFile != "" {
+		poller = handlers.NewPoller(scrapeConfig, settings.pollTargets)
+		scrapeConfig.Poller = poller
+
+		wg.Add(1)
+		go poller.Run(donePoller, &wg)
 	}
 
+	current = handlers.NewCurrentConfig(&scrapeConfig)
+
+	reloadSuccess.Set(1)
+	reloadSuccessTime.SetToCurrentTime()
+	prometheus.MustRegister(reloadSuccess, reloadSuccessTime)
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /info", func(w http.ResponseWriter, r *http.Request) {
@@ -259,9 +343,21 @@ func main() {
 
 	mux.Handle("GET /metrics", promhttp.Handler())
 
-	mux.HandleFunc("GET /scrape", handlers.ScrapeHandler(scrapeConfig))
+	mux.HandleFunc("GET /scrape", current.Handler(handlers.ScrapeHandler))
+
+	mux.HandleFunc("GET /scrape/partial", current.Handler(handlers.PartialScrapeHandler))
+
+	mux.HandleFunc("POST /-/reload", reloadHandler)
+
+	mux.HandleFunc("GET /plugins", handlers.PluginsHandler)
+
+	if poller != nil {
+		mux.HandleFunc("GET /scrape/all", poller.AllHandler)
+	}
 
-	mux.HandleFunc("GET /scrape/partial", handlers.PartialScrapeHandler(scrapeConfig))
+	if *f.eventsDestination != "" {
+		mux.HandleFunc("POST /events", subscriber.EventsHandler)
+	}
 
 	tmplIndex := template.Must(template.New("index").Parse(indexTmpl))
 	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
@@ -289,14 +385,17 @@ func main() {
 	wrappedmux := logging.LoggingHandler(instrumentation.Middleware(mux))
 
 	srv := &http.Server{
-		Addr:    ":" + *exporterPort,
+		Addr:    ":" + *f.exporterPort,
 		Handler: wrappedmux,
 	}
 
 	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
 
-	listener, err := net.Listen("tcp4", ":"+*exporterPort)
+	// SIGHUP reloads the configuration instead of stopping the app
+	reloadOnHangup()
+
+	listener, err := net.Listen("tcp4", ":"+*f.exporterPort)
 	if err != nil {
 		log.Error("starting "+app+" service failed", zap.Error(err))
 		signals <- syscall.SIGTERM
@@ -328,6 +427,14 @@ func main() {
 			tokenLifecycle <- true
 		}
 		doneRenew <- true
+
+		if subscriber != nil {
+			doneEvents <- true
+		}
+
+		if poller != nil {
+			donePoller <- true
+		}
 	}()
 
 	wg.Wait()
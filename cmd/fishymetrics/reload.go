@@ -0,0 +1,209 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/config"
+	"github.com/comcast/fishymetrics/exporter"
+	"github.com/comcast/fishymetrics/http/handlers"
+	"go.uber.org/zap"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	reloadMu sync.Mutex
+
+	reloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "fishymetrics_config_last_reload_successful",
+		Help: "Whether the last configuration reload attempt was successful.",
+	})
+	reloadSuccessTime = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "fishymetrics_config_last_reload_success_timestamp_seconds",
+		Help: "Timestamp of the last successful configuration reload.",
+	})
+)
+
+// reloadSettings are the parts of the configuration that are replaced on reload, the listeners, the vault
+// client, the events, enabling the poller and the scrape limits need a restart
+type reloadSettings struct {
+	config            *config.Config
+	excludes          map[string]interface{}
+	urlExtraParamsMap map[string]string
+	modules           map[string]*handlers.Module
+	targetTLS         []*common.TargetTLSSettings
+	metricMappings    []*exporter.MetricMapping
+	credProfiles      common.CredentialProfilesFlag
+	pollTargets       []handlers.PollTarget
+}
+
+// loadSettings builds the settings from the parsed flags and the files they point to
+func loadSettings(f *flags) (*reloadSettings, error) {
+	s := &reloadSettings{
+		excludes:          make(map[string]interface{}),
+		urlExtraParamsMap: make(map[string]string),
+		credProfiles:      *f.credProfiles,
+	}
+
+	// populate excludes map
+	if *f.driveModExclude != "" {
+		driveModPattern, err := regexp.Compile(*f.driveModExclude)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing arg --collector.drives.modules-exclude - %s", err.Error())
+		}
+		s.excludes["drive"] = driveModPattern
+	}
+
+	if *f.firmwareModExclude != "" {
+		firmwareModPattern, err := regexp.Compile(*f.firmwareModExclude)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing arg --collector.firmware.modules-exclude - %s", err.Error())
+		}
+		s.excludes["firmware"] = firmwareModPattern
+	}
+
+	refreshIntervalsMap, err := exporter.ParseRefreshIntervals(*f.refreshIntervals)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing arg --collector.refresh-intervals - %s", err.Error())
+	}
+
+	cacheMaxAgeMap, err := exporter.ParseCacheMaxAge(*f.cacheMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing arg --cache.max-age - %s", err.Error())
+	}
+
+	s.config = &config.Config{
+		BMCScheme:        *f.bmcScheme,
+		BMCTimeout:       *f.bmcTimeout,
+		SSLVerify:        *f.insecureSkipVerify,
+		User:             *f.username,
+		Pass:             *f.password,
+		Disable404Retry:  *f.disable404Retry,
+		RefreshIntervals: refreshIntervalsMap,
+		CacheMaxAge:      cacheMaxAgeMap,
+	}
+
+	// populate urlExtraParamsMap if url extra params are passed
+	if *f.urlExtraParams != "" {
+		// --url.extra.params="short_hostname:shortname,param2:alias1,param3:alias2"
+		for _, param := range strings.Split(*f.urlExtraParams, ",") {
+			kv := strings.Split(param, ":")
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("error parsing arg --url.extra-params - invalid param %q", param)
+			}
+			s.urlExtraParamsMap[kv[0]] = kv[1]
+		}
+	}
+
+	if *f.configFile != "" {
+		cf, err := handlers.LoadConfigFile(*f.configFile, s.excludes)
+		if err != nil {
+			return nil, err
+		}
+		s.modules = cf.Modules
+		s.targetTLS = cf.TargetTLS
+		s.metricMappings = cf.Metrics
+	}
+
+	if *f.pollerTargetsFile != "" {
+		if *f.pollerInterval <= 0 {
+			return nil, fmt.Errorf("arg --poller.interval must be positive")
+		}
+
+		s.pollTargets, err = handlers.LoadPollTargets(*f.pollerTargetsFile, *f.pollerInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// reload parses the flags again into a new app, flags read from a file with @<file> can change, and swaps
+// the new settings in once they are all valid. The running scrapes finish with the settings they started with
+func reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	err := func() error {
+		ra, rf := newApp()
+		if _, err := ra.Parse(os.Args[1:]); err != nil {
+			return fmt.Errorf("error parsing argument flags - %s", err.Error())
+		}
+
+		s, err := loadSettings(rf)
+		if err != nil {
+			return err
+		}
+
+		config.SetConfig(s.config)
+		common.ChassisCreds.SetProfiles(&s.credProfiles)
+		common.TargetTLS.Set(s.targetTLS)
+		exporter.MetricMappings.Set(s.metricMappings)
+
+		cfg := *current.Load()
+		cfg.Excludes = s.excludes
+		cfg.URLExtraParamsMap = s.urlExtraParamsMap
+		cfg.Modules = s.modules
+		current.Store(&cfg)
+
+		if poller != nil {
+			poller.Reload(cfg, s.pollTargets)
+		}
+
+		return nil
+	}()
+	if err != nil {
+		reloadSuccess.Set(0)
+		log.Error("failed to reload configuration", zap.Error(err))
+		return err
+	}
+
+	reloadSuccess.Set(1)
+	reloadSuccessTime.SetToCurrentTime()
+	log.Info("reloaded configuration")
+	return nil
+}
+
+// reloadOnHangup reloads the configuration on every SIGHUP instead of stopping the app
+func reloadOnHangup() {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			log.Info("SIGHUP signal caught, reloading configuration")
+			reload()
+		}
+	}()
+}
+
+// reloadHandler handles POST /-/reload requests
+func reloadHandler(w http.ResponseWriter, r *http.Request) {
+	if err := reload(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload configuration - %s", err.Error()), http.StatusInternalServerError)
+	}
+}
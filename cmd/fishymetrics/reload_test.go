@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/comcast/fishymetrics/config"
+	"github.com/comcast/fishymetrics/http/handlers"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+// setupReload points the reloads at an args file, the flags are changed by writing the file again
+func setupReload(t *testing.T) string {
+	t.Helper()
+
+	argsFile := filepath.Join(t.TempDir(), "args")
+	args := os.Args
+	os.Args = []string{app, "@" + argsFile}
+	log = zap.NewNop()
+	current = handlers.NewCurrentConfig(&handlers.ScrapeConfig{})
+	t.Cleanup(func() {
+		os.Args = args
+		config.SetConfig(&config.Config{})
+	})
+
+	return argsFile
+}
+
+func writeArgs(t *testing.T, argsFile, args string) {
+	t.Helper()
+	if err := os.WriteFile(argsFile, []byte(args), 0o600); err != nil {
+		t.Fatalf("error writing args file: %v", err)
+	}
+}
+
+func Test_Reload(t *testing.T) {
+	argsFile := setupReload(t)
+	writeArgs(t, argsFile, "--timeout=20s\n--url.extra-params=short_hostname:shortname\n")
+
+	// the flags are parsed into a new app, the ones the app started with are left as they are
+	timeout := *f.bmcTimeout
+	if err := reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if got := *f.bmcTimeout; got != timeout {
+		t.Errorf("flag --timeout = %s, want %s", got, timeout)
+	}
+
+	if got := config.GetConfig().BMCTimeout; got != 20*time.Second {
+		t.Errorf("BMCTimeout = %s, want 20s", got)
+	}
+	if got := current.Load().URLExtraParamsMap["short_hostname"]; got != "shortname" {
+		t.Errorf("URLExtraParamsMap[short_hostname] = %q, want shortname", got)
+	}
+	if got := testutil.ToFloat64(reloadSuccess); got != 1 {
+		t.Errorf("reloadSuccess = %v, want 1", got)
+	}
+}
+
+func Test_Reload_Invalid(t *testing.T) {
+	argsFile := setupReload(t)
+	writeArgs(t, argsFile, "--timeout=20s\n--url.extra-params=short_hostname:shortname\n")
+	if err := reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+
+	// the timeout and the params are valid, the drives pattern is not so none of them is applied
+	writeArgs(t, argsFile, "--timeout=30s\n--url.extra-params=short_hostname:alias\n--collector.drives.modules-exclude=(\n")
+	if err := reload(); err == nil {
+		t.Fatal("reload() error = nil, want an error")
+	}
+
+	if got := config.GetConfig().BMCTimeout; got != 20*time.Second {
+		t.Errorf("BMCTimeout = %s, want 20s", got)
+	}
+	if got := current.Load().URLExtraParamsMap["short_hostname"]; got != "shortname" {
+		t.Errorf("URLExtraParamsMap[short_hostname] = %q, want shortname", got)
+	}
+	if _, ok := current.Load().Excludes["drive"]; ok {
+		t.Error("Excludes[drive] is set, want the excludes of the last successful reload")
+	}
+	if got := testutil.ToFloat64(reloadSuccess); got != 0 {
+		t.Errorf("reloadSuccess = %v, want 0", got)
+	}
+
+	writeArgs(t, argsFile, "--timeout=30s\n--unknown\n")
+	if err := reload(); err == nil {
+		t.Fatal("reload() error = nil, want an error")
+	}
+	if got := config.GetConfig().BMCTimeout; got != 20*time.Second {
+		t.Errorf("BMCTimeout = %s, want 20s", got)
+	}
+}
+
+func Test_Reload_Hangup(t *testing.T) {
+	argsFile := setupReload(t)
+	writeArgs(t, argsFile, "--timeout=40s\n")
+
+	reloadOnHangup()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("error sending SIGHUP: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for config.GetConfig().BMCTimeout != 40*time.Second {
+		if time.Now().After(deadline) {
+			t.Fatalf("BMCTimeout = %s after SIGHUP, want 40s", config.GetConfig().BMCTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
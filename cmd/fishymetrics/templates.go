@@ -50,6 +50,7 @@ const indexTmpl string = `<html>
     <div class="links">
       <h3><a href="ignored">Ignored Hosts</a></h3>
       <h3><a href="metrics">Metrics</a></h3>
+      <h3><a href="plugins">Plugins</a></h3>
     </div>
     <form action="scrape">
       <label>Target:</label> <input type="text" name="target" placeholder="ip or fdqn"><br>
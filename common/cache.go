@@ -0,0 +1,187 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// responseCacheTTL is how long a response is kept after it was last used
+	responseCacheTTL = 24 * time.Hour
+	// responseCacheMaxBytes caps the size of the bodies kept for all the targets together, the least recently
+	// used responses of any target are evicted first
+	responseCacheMaxBytes = 64 << 20
+)
+
+var (
+	// Responses is the response cache used by FetchRevalidated
+	Responses = NewResponseCache(responseCacheMaxBytes)
+)
+
+// ResponseCache keeps the last response of the urls fetched with FetchRevalidated per target. Responses with
+// an ETag are revalidated with If-None-Match and replayed on a 304 Not Modified, responses can also be reused
+// without a request while they are younger than a max-age. The responses not used for responseCacheTTL are
+// dropped and the least recently used ones are evicted once the bodies exceed the size limit.
+type ResponseCache struct {
+	mu       sync.Mutex
+	entries  map[cacheKey]*list.Element
+	lru      *list.List
+	size     int
+	maxBytes int
+	// lastPrune is when the responses older than responseCacheTTL were last dropped
+	lastPrune time.Time
+}
+
+// cacheKey identifies a response of a target, the responses served by WithMaxAge are kept apart so a request
+// for the same url outside of WithMaxAge doesn't extend their age
+type cacheKey struct {
+	host   string
+	uri    string
+	reused bool
+}
+
+type cachedResponse struct {
+	key       cacheKey
+	etag      string
+	body      []byte
+	validated time.Time
+	used      time.Time
+}
+
+// NewResponseCache returns an empty ResponseCache keeping at most maxBytes of response bodies
+func NewResponseCache(maxBytes int) *ResponseCache {
+	return &ResponseCache{
+		entries:  make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		maxBytes: maxBytes,
+	}
+}
+
+// get returns the response of key and marks it as used, c.mu must be held
+func (c *ResponseCache) get(key cacheKey) (*cachedResponse, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	r := el.Value.(*cachedResponse)
+	r.used = time.Now()
+	c.lru.MoveToFront(el)
+	return r, true
+}
+
+// set replaces the response of its key and evicts the responses over the size limit, c.mu must be held
+func (c *ResponseCache) set(r *cachedResponse) {
+	now := time.Now()
+	c.prune(now)
+
+	r.used = now
+	if el, ok := c.entries[r.key]; ok {
+		c.remove(el)
+	}
+	c.entries[r.key] = c.lru.PushFront(r)
+	c.size += len(r.body)
+
+	for c.size > c.maxBytes && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *ResponseCache) remove(el *list.Element) {
+	r := c.lru.Remove(el).(*cachedResponse)
+	delete(c.entries, r.key)
+	c.size -= len(r.body)
+}
+
+// prune drops the responses not used for responseCacheTTL, it runs at most once an hour
+func (c *ResponseCache) prune(now time.Time) {
+	if now.Sub(c.lastPrune) < time.Hour {
+		return
+	}
+	c.lastPrune = now
+
+	for el := c.lru.Back(); el != nil && now.Sub(el.Value.(*cachedResponse).used) > responseCacheTTL; el = c.lru.Back() {
+		c.remove(el)
+	}
+}
+
+// etag returns the stored ETag of uri for the target host
+func (c *ResponseCache) etag(host, uri string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[cacheKey{host: host, uri: uri}]; ok {
+		return el.Value.(*cachedResponse).etag
+	}
+	return ""
+}
+
+// replay returns the stored body of uri after the BMC reported it is not modified
+func (c *ResponseCache) replay(host, uri string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.get(cacheKey{host: host, uri: uri})
+	if !ok || r.etag == "" {
+		return nil, false
+	}
+	r.validated = time.Now()
+	return r.body, true
+}
+
+// store keeps the body of uri, responses without an ETag are only kept for urls that are already cached
+func (c *ResponseCache) store(host, uri, etag string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey{host: host, uri: uri}
+	if _, ok := c.entries[key]; !ok && etag == "" {
+		return
+	}
+	c.set(&cachedResponse{key: key, etag: etag, body: body, validated: time.Now()})
+}
+
+// WithMaxAge returns a fetch function that returns the stored body of uri while it is younger than maxAge
+// and calls fetch otherwise, fetch is returned as is when maxAge is not positive
+func (c *ResponseCache) WithMaxAge(fetch func() ([]byte, error), uri, host string, maxAge time.Duration) func() ([]byte, error) {
+	if maxAge <= 0 {
+		return fetch
+	}
+
+	key := cacheKey{host: host, uri: uri, reused: true}
+	return func() ([]byte, error) {
+		c.mu.Lock()
+		if r, ok := c.get(key); ok && time.Since(r.validated) < maxAge {
+			c.mu.Unlock()
+			return r.body, nil
+		}
+		c.mu.Unlock()
+
+		body, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.set(&cachedResponse{key: key, body: body, validated: time.Now()})
+		c.mu.Unlock()
+
+		return body, nil
+	}
+}
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Test that responses with an ETag are revalidated with If-None-Match and replayed on a 304 Not Modified.
+func Test_Fetch_ETag_Replay(t *testing.T) {
+	var hits, notModified int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"Name":"firmware"}`))
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 0
+	// a transport without a proxy keeps the environment proxy settings from being read before the proxy tests set them
+	client.HTTPClient.Transport = &http.Transport{}
+
+	uri := server.URL + "/redfish/v1/UpdateService/FirmwareInventory/1"
+	for i := 0; i < 3; i++ {
+		body, err := FetchRevalidated(uri, "etag.example", "", client)()
+		if err != nil {
+			t.Fatalf("Fetch error: %v", err)
+		}
+		if string(body) != `{"Name":"firmware"}` {
+			t.Fatalf("unexpected body: %s", string(body))
+		}
+	}
+
+	if got, want := atomic.LoadInt32(&notModified), int32(2); got != want {
+		t.Fatalf("not modified responses = %d, want %d", got, want)
+	}
+
+	// the cache is per target
+	if _, err := FetchRevalidated(uri, "other.example", "", client)(); err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&notModified), int32(2); got != want {
+		t.Fatalf("not modified responses = %d, want %d", got, want)
+	}
+	if got, want := atomic.LoadInt32(&hits), int32(4); got != want {
+		t.Fatalf("requests = %d, want %d", got, want)
+	}
+
+	// the fast-changing resources are not revalidated
+	if _, err := Fetch(uri, "etag.example", "", client)(); err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&notModified), int32(2); got != want {
+		t.Fatalf("not modified responses = %d, want %d", got, want)
+	}
+}
+
+// Test that the least recently used responses are evicted over the size limit and idle ones are dropped.
+func Test_ResponseCache_Eviction(t *testing.T) {
+	c := NewResponseCache(10)
+
+	c.store("a.example", "/1", `"1"`, []byte("aaaa"))
+	c.store("a.example", "/2", `"2"`, []byte("bbbb"))
+	if _, ok := c.replay("a.example", "/1"); !ok {
+		t.Fatalf("response /1 not cached")
+	}
+
+	// /2 is the least recently used response
+	c.store("b.example", "/1", `"3"`, []byte("cccc"))
+	if c.etag("a.example", "/2") != "" {
+		t.Fatalf("response /2 not evicted")
+	}
+	if c.etag("a.example", "/1") == "" || c.etag("b.example", "/1") == "" {
+		t.Fatalf("recently used responses evicted")
+	}
+	if c.size != 8 {
+		t.Fatalf("size = %d, want 8", c.size)
+	}
+
+	// responses not used within the ttl are dropped
+	c.entries[cacheKey{host: "a.example", uri: "/1"}].Value.(*cachedResponse).used = time.Now().Add(-2 * responseCacheTTL)
+	c.lastPrune = time.Time{}
+	c.store("b.example", "/2", `"4"`, []byte("d"))
+	if c.etag("a.example", "/1") != "" {
+		t.Fatalf("idle response not dropped")
+	}
+	if c.size != 5 {
+		t.Fatalf("size = %d, want 5", c.size)
+	}
+}
+
+// Test that responses are reused without a request while they are younger than the max-age.
+func Test_ResponseCache_WithMaxAge(t *testing.T) {
+	var calls int
+	fetch := func() ([]byte, error) {
+		calls++
+		return []byte(`{"Name":"dimm"}`), nil
+	}
+
+	c := NewResponseCache(responseCacheMaxBytes)
+	uri := "https://maxage.example/redfish/v1/Systems/1/Memory/proc1dimm1"
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.WithMaxAge(fetch, uri, "maxage.example", time.Hour)(); err != nil {
+			t.Fatalf("WithMaxAge error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("fetch calls = %d, want 1", calls)
+	}
+
+	// an expired response is fetched again
+	c.entries[cacheKey{host: "maxage.example", uri: uri, reused: true}].Value.(*cachedResponse).validated = time.Now().Add(-2 * time.Hour)
+	if _, err := c.WithMaxAge(fetch, uri, "maxage.example", time.Hour)(); err != nil {
+		t.Fatalf("WithMaxAge error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("fetch calls = %d, want 2", calls)
+	}
+
+	// no max-age always fetches
+	if _, err := c.WithMaxAge(fetch, uri, "maxage.example", 0)(); err != nil {
+		t.Fatalf("WithMaxAge error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("fetch calls = %d, want 3", calls)
+	}
+}
@@ -78,16 +78,19 @@ type CredentialProfilesFlag struct {
 	Profiles []ProfileFlag `json:"profiles" yaml:"profiles"`
 }
 
+// Set parses the profiles, they are used once they are passed to ChassisCredentials.SetProfiles
 func (cp *CredentialProfilesFlag) Set(value string) error {
 	err := yaml.Unmarshal([]byte(value), cp)
 	if err != nil {
 		// if json was passed in we will attempt to unmarshal differently
 		err := json.Unmarshal([]byte(value), cp)
 		if err != nil {
-			panic(fmt.Errorf("error parsing argument flag \"--credentials.profiles\" - %s", err.Error()))
+			return fmt.Errorf("error parsing argument flag \"--credentials.profiles\" - %s", err.Error())
 		}
 	}
-	ChassisCreds.populateProfiles(cp)
+	if len(cp.Profiles) == 0 {
+		return fmt.Errorf("error parsing argument flag \"--credentials.profiles\" - no profiles")
+	}
 	return nil
 }
 
@@ -114,9 +117,19 @@ func (c *ChassisCredentials) Set(key string, value *Credential) {
 	c.Creds[key] = value
 }
 
-func (c *ChassisCredentials) populateProfiles(profiles *CredentialProfilesFlag) {
+// SetProfiles replaces the credential profiles, the cached credentials are dropped so they are read again
+// with the new profiles
+func (c *ChassisCredentials) SetProfiles(profiles *CredentialProfilesFlag) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+
+	c.Profiles = make(map[string]*fishy_vault.SecretProperties)
+	c.DefaultProfile = ""
+	c.Creds = make(map[string]*Credential)
+	if len(profiles.Profiles) == 0 {
+		return
+	}
+
 	// default profile is the first one in the list
 	c.DefaultProfile = profiles.Profiles[0].Name
 
@@ -143,14 +156,19 @@ func (c *ChassisCredentials) GetCredentials(ctx context.Context, profile, target
 		return nil, fmt.Errorf("vault client not configured")
 	}
 
+	// the profiles are replaced on reload
+	c.mu.Lock()
+	profiles, defaultProfile := c.Profiles, c.DefaultProfile
+	c.mu.Unlock()
+
 	// check that at least 1 profile is present
-	if len(c.Profiles) < 1 {
+	if len(profiles) < 1 {
 		return nil, fmt.Errorf("no credential profiles configured")
 	}
 
 	// if profile is set but not in hashmap we will error
 	if profile != "" {
-		credProf, ok := c.Profiles[profile]
+		credProf, ok := profiles[profile]
 		if !ok {
 			return nil, fmt.Errorf("profile \"%s\" not found", profile)
 		}
@@ -159,7 +177,7 @@ func (c *ChassisCredentials) GetCredentials(ctx context.Context, profile, target
 		credProfCopy = &copy
 	} else {
 		// if profile is empty string we make a copy of the default profile
-		copy := *c.Profiles[c.DefaultProfile]
+		copy := *profiles[defaultProfile]
 		credProfCopy = &copy
 	}
 
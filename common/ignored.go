@@ -117,6 +117,7 @@ func TestConn(w http.ResponseWriter, r *http.Request) {
 		},
 		TLSHandshakeTimeout: 10 * time.Second,
 	}
+	TargetTLS.Get(h.H).Apply(tr.TLSClientConfig)
 
 	client := &http.Client{
 		Timeout:   10 * time.Second,
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultQuirks are the quirks of the targets whose vendor isn't detected yet
+	DefaultQuirks = Quirks{
+		// cisco devices respond in a non idiomatic manner to back to back requests
+		RequestDelay: 100 * time.Millisecond,
+		// this header is required by iDRAC9 with FW ver. 3.xx and 4.xx
+		Accept: "application/json",
+	}
+
+	// TargetQuirks holds the quirks of the vendor detected for each target
+	TargetQuirks = NewQuirksStore()
+)
+
+// Quirks are the request settings that depend on the vendor of the BMC
+type Quirks struct {
+	// RequestDelay is the delay before each request sent to the BMC
+	RequestDelay time.Duration
+	// Accept is the Accept header of the requests, none is sent when empty
+	Accept string
+}
+
+// QuirksStore keeps the quirks of each target
+type QuirksStore struct {
+	mu     sync.RWMutex
+	quirks map[string]Quirks
+}
+
+// NewQuirksStore returns an empty QuirksStore
+func NewQuirksStore() *QuirksStore {
+	return &QuirksStore{
+		quirks: make(map[string]Quirks),
+	}
+}
+
+// Set replaces the quirks of host
+func (s *QuirksStore) Set(host string, q Quirks) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quirks[host] = q
+}
+
+// Get returns the quirks of host, DefaultQuirks when none are set
+func (s *QuirksStore) Get(host string) Quirks {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if q, ok := s.quirks[host]; ok {
+		return q
+	}
+	return DefaultQuirks
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+)
+
+// Test that the Accept header is only sent to the targets whose quirks ask for it.
+func Test_BuildRequest_Accept(t *testing.T) {
+	TargetQuirks.Set("quirks-accept", Quirks{Accept: "application/json"})
+	TargetQuirks.Set("quirks-none", Quirks{})
+
+	tests := []struct {
+		host   string
+		accept string
+	}{
+		{host: "quirks-accept", accept: "application/json"},
+		{host: "quirks-none", accept: ""},
+		// the targets whose vendor isn't detected yet use the default quirks
+		{host: "quirks-unknown", accept: "application/json"},
+	}
+
+	for _, test := range tests {
+		req, err := BuildRequest("http://"+test.host+"/redfish/v1/", test.host)
+		if err != nil {
+			t.Fatalf("BuildRequest() error = %v", err)
+		}
+		if got := req.Header.Get("Accept"); got != test.accept {
+			t.Errorf("Accept of %s = %q, want %q", test.host, got, test.accept)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/comcast/fishymetrics/config"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+var (
+	// Sessions holds the Redfish sessions of the targets scraped with session authentication, the requests to
+	// those targets send the session token instead of basic auth
+	Sessions = NewSessionStore()
+)
+
+// SessionStore keeps one Redfish session per target, the sessions are reused across scrapes and are
+// recreated when the BMC rejects their token
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	// service is the url of the SessionService sessions collection the session was created in
+	service string
+	token   string
+}
+
+// NewSessionStore returns an empty SessionStore
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		sessions: make(map[string]*session),
+	}
+}
+
+// Login creates a session for host in the service sessions collection unless host already has one
+func (s *SessionStore) Login(service, host string, client *retryablehttp.Client) error {
+	if _, ok := s.token(host); ok {
+		return nil
+	}
+	return s.create(service, host, client)
+}
+
+func (s *SessionStore) token(host string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[host]
+	if !ok {
+		return "", false
+	}
+	return sess.token, true
+}
+
+// renew replaces the session of host after the BMC rejected its token, sessions expire when the BMC
+// idles them out, it is a no-op for the targets that use basic auth
+func (s *SessionStore) renew(host string, client *retryablehttp.Client) error {
+	s.mu.Lock()
+	sess, ok := s.sessions[host]
+	delete(s.sessions, host)
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return s.create(sess.service, host, client)
+}
+
+func (s *SessionStore) create(service, host string, client *retryablehttp.Client) error {
+	var user, password string
+	if c, ok := ChassisCreds.Get(host); ok {
+		user = c.User
+		password = c.Pass
+	} else {
+		user = config.GetConfig().User
+		password = config.GetConfig().Pass
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"UserName": user,
+		"Password": password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal session request body - %v", err)
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, service, body)
+	if err != nil {
+		return fmt.Errorf("failed to build session request - %v", err)
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := DoRequest(client, req)
+	if err != nil {
+		return err
+	}
+	defer EmptyAndCloseBody(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrInvalidCredential
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		return fmt.Errorf("error creating session - HTTP status %d", resp.StatusCode)
+	}
+
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		return fmt.Errorf("error creating session - no X-Auth-Token in the response")
+	}
+
+	s.mu.Lock()
+	s.sessions[host] = &session{
+		service: service,
+		token:   token,
+	}
+	s.mu.Unlock()
+
+	return nil
+}
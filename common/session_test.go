@@ -0,0 +1,68 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Test that session authenticated requests send the session token and that an expired token is renewed.
+func Test_Sessions_LoginAndRenew(t *testing.T) {
+	var logins int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/redfish/v1/SessionService/Sessions" {
+			n := atomic.AddInt32(&logins, 1)
+			w.Header().Set("X-Auth-Token", map[int32]string{1: "expired", 2: "fresh"}[n])
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Errorf("request to %s used basic auth", r.URL.Path)
+		}
+		if r.Header.Get("X-Auth-Token") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Name":"chassis"}`))
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 0
+	client.RetryWaitMin = time.Millisecond
+	// a transport without a proxy keeps the environment proxy settings from being read before the proxy tests set them
+	client.HTTPClient.Transport = &http.Transport{}
+
+	host := "session.example"
+	defer func() {
+		Sessions.mu.Lock()
+		delete(Sessions.sessions, host)
+		Sessions.mu.Unlock()
+	}()
+
+	for i := 0; i < 2; i++ {
+		if err := Sessions.Login(server.URL+"/redfish/v1/SessionService/Sessions", host, client); err != nil {
+			t.Fatalf("Login error: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&logins); got != 1 {
+		t.Fatalf("logins = %d, want 1", got)
+	}
+
+	body, err := Fetch(server.URL+"/redfish/v1/Chassis/1", host, "", client)()
+	if err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if string(body) != `{"Name":"chassis"}` {
+		t.Fatalf("body = %s", body)
+	}
+	if got := atomic.LoadInt32(&logins); got != 2 {
+		t.Fatalf("logins = %d, want the expired session to be renewed", got)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// TargetTLS holds the TLS settings of the target patterns of the config file
+	TargetTLS = &TargetTLSList{}
+
+	tlsVersions = map[string]uint16{
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	}
+)
+
+// TLSSettings are the TLS settings of the connections to a BMC, they replace the --insecure-skip-verify flag
+type TLSSettings struct {
+	InsecureSkipVerify *bool `yaml:"insecureSkipVerify,omitempty"`
+	// CAFile is a PEM bundle of the CAs the BMC certificates are verified against instead of the system roots
+	CAFile string `yaml:"caFile,omitempty"`
+	// ServerName is the name sent with SNI and verified against the BMC certificate instead of the target
+	ServerName string `yaml:"serverName,omitempty"`
+	// CertFile and KeyFile are the PEM client certificate and key of the BMCs that require mTLS
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	// MinVersion is the minimum TLS version, one of 1.0, 1.1, 1.2 or 1.3
+	MinVersion string `yaml:"minVersion,omitempty"`
+	// PinnedSHA256 are the hex SHA-256 fingerprints of the accepted BMC certificates, the certificate is trusted
+	// when it matches one of them instead of being verified against the CAs
+	PinnedSHA256 []string `yaml:"pinnedSHA256,omitempty"`
+
+	roots      *x509.CertPool
+	cert       *tls.Certificate
+	minVersion uint16
+	pins       [][]byte
+}
+
+// Load validates the settings and reads the files they point to
+func (s *TLSSettings) Load() error {
+	if s.CAFile != "" {
+		pem, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return fmt.Errorf("error reading caFile - %s", err.Error())
+		}
+		s.roots = x509.NewCertPool()
+		if !s.roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in caFile %s", s.CAFile)
+		}
+	}
+
+	if s.CertFile != "" || s.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return fmt.Errorf("error loading client certificate - %s", err.Error())
+		}
+		s.cert = &cert
+	}
+
+	if s.MinVersion != "" {
+		v, ok := tlsVersions[s.MinVersion]
+		if !ok {
+			return fmt.Errorf("invalid minVersion %s, valid versions are: 1.0, 1.1, 1.2, 1.3", s.MinVersion)
+		}
+		s.minVersion = v
+	}
+
+	s.pins = nil
+	for _, p := range s.PinnedSHA256 {
+		pin, err := hex.DecodeString(strings.ReplaceAll(p, ":", ""))
+		if err != nil || len(pin) != sha256.Size {
+			return fmt.Errorf("invalid pinnedSHA256 %s, it must be a hex SHA-256 fingerprint", p)
+		}
+		s.pins = append(s.pins, pin)
+	}
+
+	return nil
+}
+
+// Apply sets the settings on c, a nil TLSSettings leaves c unchanged
+func (s *TLSSettings) Apply(c *tls.Config) {
+	if s == nil {
+		return
+	}
+
+	if s.InsecureSkipVerify != nil {
+		c.InsecureSkipVerify = *s.InsecureSkipVerify
+	}
+	if s.roots != nil {
+		c.RootCAs = s.roots
+	}
+	if s.ServerName != "" {
+		c.ServerName = s.ServerName
+	}
+	if s.cert != nil {
+		c.Certificates = []tls.Certificate{*s.cert}
+	}
+	if s.minVersion != 0 {
+		c.MinVersion = s.minVersion
+	}
+	if len(s.pins) > 0 {
+		// BMCs mostly serve self-signed certificates, the pins replace the verification against the CAs
+		c.InsecureSkipVerify = true
+		c.VerifyConnection = s.verifyPins
+	}
+}
+
+func (s *TLSSettings) verifyPins(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("no certificate served by %s", cs.ServerName)
+	}
+
+	sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+	for _, pin := range s.pins {
+		if bytes.Equal(pin, sum[:]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("certificate of %s with SHA-256 %x doesn't match any pin", cs.ServerName, sum)
+}
+
+// TargetTLSSettings are the TLS settings of the targets matching a regex, the regex matches the whole target
+type TargetTLSSettings struct {
+	Targets     string `yaml:"targets"`
+	TLSSettings `yaml:",inline"`
+
+	re *regexp.Regexp
+}
+
+// Load compiles the targets regex and loads the TLS settings, the regex is anchored so 10.0.0.1 doesn't match
+// 10.0.0.10
+func (t *TargetTLSSettings) Load() error {
+	re, err := regexp.Compile("^(?:" + t.Targets + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid targets regex - %s", err.Error())
+	}
+	t.re = re
+
+	return t.TLSSettings.Load()
+}
+
+// TargetTLSList picks the TLS settings of a target from a list of target patterns
+type TargetTLSList struct {
+	mu      sync.RWMutex
+	targets []*TargetTLSSettings
+}
+
+// Set replaces the target patterns
+func (l *TargetTLSList) Set(targets []*TargetTLSSettings) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.targets = targets
+}
+
+// Get returns the TLS settings of the first pattern matching target, nil when none does
+func (l *TargetTLSList) Get(target string) *TLSSettings {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, t := range l.targets {
+		if t.re.MatchString(target) {
+			return &t.TLSSettings
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test that the CA bundle and SNI override of the settings verify the BMC certificate.
+func Test_TLSSettings_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+
+	for _, tc := range []struct {
+		serverName string
+		wantErr    bool
+	}{
+		// the httptest certificate is valid for example.com
+		{serverName: "example.com"},
+		{serverName: "bmc.invalid", wantErr: true},
+	} {
+		s := &TLSSettings{CAFile: caFile, ServerName: tc.serverName, MinVersion: "1.2"}
+		if err := s.Load(); err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+
+		c := &tls.Config{}
+		s.Apply(c)
+		if c.MinVersion != tls.VersionTLS12 {
+			t.Fatalf("MinVersion = %x, want TLS 1.2", c.MinVersion)
+		}
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: c}}
+		resp, err := client.Get(server.URL)
+		if tc.wantErr {
+			if err == nil {
+				resp.Body.Close()
+				t.Fatalf("request with server name %s succeeded, want a verification error", tc.serverName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("request error: %v", err)
+		}
+		resp.Body.Close()
+	}
+}
+
+func Test_TargetTLSList_Get(t *testing.T) {
+	targets := []*TargetTLSSettings{
+		{Targets: `10\.0\.0\.1`, TLSSettings: TLSSettings{ServerName: "bmc1"}},
+		{Targets: `^10\.0\.1\..*`, TLSSettings: TLSSettings{ServerName: "rack1"}},
+		{Targets: `10\..*`, TLSSettings: TLSSettings{ServerName: "dc"}},
+	}
+	for _, target := range targets {
+		if err := target.Load(); err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+	}
+
+	l := &TargetTLSList{}
+	l.Set(targets)
+
+	// the patterns match the whole target, 10.0.0.1 isn't a prefix of 10.0.0.10
+	for target, want := range map[string]string{"10.0.0.1": "bmc1", "10.0.0.10": "dc", "10.0.0.199": "dc", "10.0.1.5": "rack1", "10.2.0.1": "dc"} {
+		if s := l.Get(target); s == nil || s.ServerName != want {
+			t.Fatalf("Get(%s) = %+v, want server name %s", target, s, want)
+		}
+	}
+	for _, target := range []string{"192.168.0.1", "110.0.0.1"} {
+		if s := l.Get(target); s != nil {
+			t.Fatalf("Get(%s) = %+v, want nil", target, s)
+		}
+	}
+
+	for _, invalid := range []TLSSettings{
+		{MinVersion: "1.4"},
+		{PinnedSHA256: []string{"abcd"}},
+		{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{CertFile: "cert.pem"},
+	} {
+		if err := invalid.Load(); err == nil {
+			t.Fatalf("Load returned nil error for %+v", invalid)
+		}
+	}
+}
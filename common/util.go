@@ -18,6 +18,7 @@ package common
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -38,20 +39,45 @@ type metricHandler func([]byte) error
 type Handler metricHandler
 
 func Fetch(uri, host, profile string, client *retryablehttp.Client) func() ([]byte, error) {
+	return fetch(uri, host, profile, client, false)
+}
+
+// FetchRevalidated is Fetch for the slow-changing resources, the responses with an ETag are kept in Responses and
+// revalidated with If-None-Match on the next fetch, a 304 Not Modified replays the kept body
+func FetchRevalidated(uri, host, profile string, client *retryablehttp.Client) func() ([]byte, error) {
+	return fetch(uri, host, profile, client, true)
+}
+
+func fetch(uri, host, profile string, client *retryablehttp.Client, revalidate bool) func() ([]byte, error) {
 	retryCount := 0
 
 	return func() ([]byte, error) {
-		// Add a 100 milliseconds delay in between requests because cisco devices respond in a non idiomatic manner
-		time.Sleep(100 * time.Millisecond)
+		quirks := TargetQuirks.Get(host)
+		if quirks.RequestDelay > 0 {
+			time.Sleep(quirks.RequestDelay)
+		}
 		req, err := BuildRequest(uri, host)
 		if err != nil {
 			return nil, err
 		}
+		// revalidate the cached response, an unchanged resource is answered with a 304 Not Modified
+		var etag string
+		if revalidate {
+			etag = Responses.etag(host, uri)
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
 		resp, err := DoRequest(client, req)
 		if err != nil {
 			return nil, err
 		}
 		defer EmptyAndCloseBody(resp)
+		if resp.StatusCode == http.StatusNotModified {
+			if body, ok := Responses.replay(host, uri); ok {
+				return body, nil
+			}
+		}
 		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 			switch resp.StatusCode {
 			case http.StatusNotFound:
@@ -83,15 +109,23 @@ func Fetch(uri, host, profile string, client *retryablehttp.Client) func() ([]by
 						return nil, fmt.Errorf("issue retrieving credentials from vault using target: %s", host)
 					}
 					ChassisCreds.Set(host, credential)
-				} else {
+				} else if _, ok := Sessions.token(host); !ok {
 					return nil, ErrInvalidCredential
 				}
 
+				// the session token of the target may have expired
+				if err := Sessions.renew(host, client); err != nil {
+					return nil, err
+				}
+
 				// build new request with updated credentials
 				req, err = BuildRequest(uri, host)
 				if err != nil {
 					return nil, err
 				}
+				if etag != "" {
+					req.Header.Set("If-None-Match", etag)
+				}
 
 				time.Sleep(client.RetryWaitMin)
 
@@ -106,6 +140,11 @@ func Fetch(uri, host, profile string, client *retryablehttp.Client) func() ([]by
 				if resp.StatusCode == http.StatusUnauthorized {
 					return nil, ErrInvalidCredential
 				}
+				if resp.StatusCode == http.StatusNotModified {
+					if body, ok := Responses.replay(host, uri); ok {
+						return body, nil
+					}
+				}
 			default:
 				return nil, fmt.Errorf("HTTP status %d", resp.StatusCode)
 			}
@@ -115,6 +154,9 @@ func Fetch(uri, host, profile string, client *retryablehttp.Client) func() ([]by
 		if err != nil {
 			return nil, fmt.Errorf("error reading Response Body - %v", err)
 		}
+		if revalidate {
+			Responses.store(host, uri, resp.Header.Get("ETag"), body)
+		}
 		return body, err
 	}
 }
@@ -129,7 +171,14 @@ func EmptyAndCloseBody(resp *http.Response) {
 }
 
 func BuildRequest(uri, host string) (*retryablehttp.Request, error) {
+	return BuildRequestWithBody(http.MethodGet, uri, host, nil)
+}
+
+// BuildRequestWithBody builds an authenticated request for the methods that change resources on the BMC,
+// a non nil body is sent as json
+func BuildRequestWithBody(method, uri, host string, body interface{}) (*retryablehttp.Request, error) {
 	var user, password string
+	var rawBody interface{}
 
 	if c, ok := ChassisCreds.Get(host); ok {
 		user = c.User
@@ -140,13 +189,29 @@ func BuildRequest(uri, host string) (*retryablehttp.Request, error) {
 		password = config.GetConfig().Pass
 	}
 
-	req, err := retryablehttp.NewRequest(http.MethodGet, uri, nil)
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body - %v", err)
+		}
+		rawBody = b
+	}
+
+	req, err := retryablehttp.NewRequest(method, uri, rawBody)
 	if err != nil || req == nil {
 		return nil, fmt.Errorf("failed to build retryable request - %v", err)
 	}
-	req.SetBasicAuth(user, password)
-	// this header is required by iDRAC9 with FW ver. 3.xx and 4.xx
-	req.Header.Add("Accept", "application/json")
+	if token, ok := Sessions.token(host); ok {
+		req.Header.Set("X-Auth-Token", token)
+	} else {
+		req.SetBasicAuth(user, password)
+	}
+	if accept := TargetQuirks.Get(host).Accept; accept != "" {
+		req.Header.Add("Accept", accept)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	return req, nil
 }
@@ -17,7 +17,7 @@
 package config
 
 import (
-	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,28 +28,33 @@ type Config struct {
 	User             string
 	Pass             string
 	Disable404Retry  bool
+	RefreshIntervals map[string]time.Duration
+	// Deprecated: CacheMaxAge is the refresh interval of the components without one in RefreshIntervals
+	CacheMaxAge map[string]time.Duration
 }
 
 var (
-	config *Config
-	once   sync.Once
+	config atomic.Pointer[Config]
 )
 
+// NewConfig sets the configuration unless one is already set
 func NewConfig(c *Config) {
-	once.Do(func() {
-		if c != nil {
-			config = c
-		} else {
-			config = &Config{}
-		}
-	})
+	if c == nil {
+		c = &Config{}
+	}
+	config.CompareAndSwap(nil, c)
+}
+
+// SetConfig replaces the configuration on reload, the requests already sent keep the settings they were built with
+func SetConfig(c *Config) {
+	config.Store(c)
 }
 
 func GetConfig() *Config {
-	if config != nil {
-		return config
+	if c := config.Load(); c != nil {
+		return c
 	}
 
 	NewConfig(nil)
-	return config
+	return config.Load()
 }
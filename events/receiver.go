@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"github.com/comcast/fishymetrics/oem"
+	"go.uber.org/zap"
+)
+
+var (
+	// messageIDRegex matches a MessageId, the registry, its version and the message key, i.e. Base.1.0.Success
+	messageIDRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]{0,63})\.[0-9]{1,4}\.[0-9]{1,4}(\.[0-9]{1,4})?\.[A-Za-z][A-Za-z0-9]{0,63}$`)
+)
+
+// EventsHandler handles POST /events requests pushed by the subscribed targets, events are only accepted with
+// the random Context of a subscription, they are counted by MessageId and severity and forwarded to the logger
+func (s *Subscriber) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	var event oem.Event
+	log := zap.L()
+	ctx := r.Context()
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEventSize)).Decode(&event)
+	if err != nil {
+		log.Error("error decoding redfish event", zap.Error(err), zap.String("remote_addr", r.RemoteAddr),
+			zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+		http.Error(w, "invalid event payload", http.StatusBadRequest)
+		return
+	}
+
+	for _, record := range event.Events {
+		// the subscription Context is a token mapped to the target when subscribing
+		token := record.Context
+		if token == "" {
+			token = event.Context
+		}
+
+		target, ok := s.lookup(token)
+		if !ok {
+			s.eventsDroppedTotal.Inc()
+			log.Debug("dropped redfish event with unknown context", zap.String("remote_addr", r.RemoteAddr),
+				zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+			continue
+		}
+
+		s.record(ctx, target, record)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// record counts the event record of target and forwards it to the logger, this is shared by every event source
+func (s *Subscriber) record(ctx context.Context, target string, record oem.EventRecord) {
+	severity := eventSeverity(record)
+	registry, messageID := eventMessageID(record)
+	s.eventsTotal.WithLabelValues(target, registry, messageID, severity).Inc()
+
+	zap.L().Info("redfish event",
+		zap.String("target", target),
+		zap.String("event_id", record.EventID),
+		zap.String("event_type", record.EventType),
+		zap.String("event_timestamp", record.EventTimestamp),
+		zap.String("severity", severity),
+		zap.String("message", record.Message),
+		zap.String("message_id", record.MessageID),
+		zap.String("origin_of_condition", record.OriginOfCondition.URL),
+		zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+}
+
+// eventSeverity returns the severity of the event record, MessageSeverity replaces the deprecated Severity property.
+// Values outside of the Redfish Health enum are Unknown.
+func eventSeverity(record oem.EventRecord) string {
+	severity := record.MessageSeverity
+	if severity == "" {
+		severity = record.Severity
+	}
+
+	switch severity {
+	case "OK", "Warning", "Critical":
+		return severity
+	}
+	return "Unknown"
+}
+
+// eventMessageID returns the message registry and the MessageId of the event record. The MessageIds that aren't
+// a registry, its version and a message key are Unknown so the label values stay bounded by the registries
+func eventMessageID(record oem.EventRecord) (string, string) {
+	m := messageIDRegex.FindStringSubmatch(record.MessageID)
+	if m == nil {
+		return "Unknown", "Unknown"
+	}
+	return m[1], record.MessageID
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/exporter"
+	"github.com/comcast/fishymetrics/oem"
+	"go.uber.org/zap"
+)
+
+var (
+	// sseMinBackoff and sseMaxBackoff bound the wait before reconnecting a stream, the wait doubles after
+	// every failed connection and goes back to sseMinBackoff once a connection succeeds
+	sseMinBackoff = 1 * time.Second
+	sseMaxBackoff = 5 * time.Minute
+)
+
+// startStream opens the server-sent events stream of target in its own go routine with the client settings
+// carried by parent, it runs until the target stops being scraped or the Subscriber is stopped
+func (s *Subscriber) startStream(parent context.Context, target, uri string, sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub.pending = false
+	sub.attempted = time.Now()
+	if s.stopped {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	sub.cancel = cancel
+	sub.location = uri
+
+	s.streams.Add(1)
+	go s.stream(ctx, target, uri, sub)
+
+	zap.L().Info("streaming event service", zap.String("target", target), zap.String("server_sent_event_uri", uri))
+}
+
+// stream keeps the server-sent events stream of target connected, reconnecting with an exponential backoff
+// and resuming from the last event received
+func (s *Subscriber) stream(ctx context.Context, target, uri string, sub *subscription) {
+	defer s.streams.Done()
+	backoff := sseMinBackoff
+
+	for {
+		connected, err := s.consume(ctx, target, uri, sub)
+		if ctx.Err() != nil {
+			zap.L().Info("stopped streaming event service", zap.String("target", target))
+			return
+		}
+		if connected {
+			backoff = sseMinBackoff
+		}
+
+		zap.L().Error("event service stream disconnected", zap.Error(err), zap.String("target", target),
+			zap.Duration("backoff", backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		s.streamReconnects.WithLabelValues(target).Inc()
+		backoff = min(backoff*2, sseMaxBackoff)
+	}
+}
+
+// consume reads the server-sent events stream of target until it ends, it reports if the connection was established
+func (s *Subscriber) consume(ctx context.Context, target, uri string, sub *subscription) (bool, error) {
+	base, err := targetURL(target)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := common.BuildRequest(base+uri, target)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	s.mu.Lock()
+	if sub.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", sub.lastEventID)
+	}
+	s.mu.Unlock()
+
+	// the stream is long lived, use the underlying client without its timeout and let stream handle the retries
+	client := exporter.NewHTTPClient(ctx).HTTPClient
+	client.Timeout = 0
+
+	resp, err := client.Do(req.Request.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer common.EmptyAndCloseBody(resp)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return false, common.ErrInvalidCredential
+	} else if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	var id string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// a blank line dispatches the event, lines starting with a colon are comments used as keep alives
+		if line == "" {
+			if len(data) > 0 {
+				s.dispatch(ctx, target, strings.Join(data, "\n"))
+			}
+			if id != "" {
+				s.mu.Lock()
+				sub.lastEventID = id
+				s.mu.Unlock()
+			}
+			id, data = "", nil
+			continue
+		} else if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return true, err
+	}
+
+	return true, errors.New("stream closed by the BMC")
+}
+
+// dispatch counts and forwards the records of a server-sent event
+func (s *Subscriber) dispatch(ctx context.Context, target, data string) {
+	var event oem.Event
+
+	err := json.Unmarshal([]byte(data), &event)
+	if err != nil {
+		zap.L().Error("error decoding redfish event", zap.Error(err), zap.String("target", target))
+		return
+	}
+
+	for _, record := range event.Events {
+		s.record(ctx, target, record)
+	}
+}
@@ -0,0 +1,478 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/config"
+	"github.com/comcast/fishymetrics/exporter"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// subscribeRetry is how long to wait before trying to subscribe to a target again after a failure
+	subscribeRetry = 5 * time.Minute
+	// maxEventSize limits the size of the events accepted on the /events route
+	maxEventSize = 1 << 20
+)
+
+// Subscriber registers fishymetrics as an EventService subscriber on the scraped targets and receives the
+// events they push to the /events route
+type Subscriber struct {
+	// Destination is the url of the /events route as reachable from the BMCs
+	Destination string
+	// TTL is how long a subscription is used before it is recreated, the subscriptions of targets that
+	// have not been scraped within TTL are deleted
+	TTL time.Duration
+	// SSE streams the events from the EventService ServerSentEventUri of the targets that support it
+	// instead of subscribing Destination
+	SSE        bool
+	Registerer prometheus.Registerer
+
+	mu   sync.Mutex
+	subs map[string]*subscription
+	// tokens maps the Context of the push subscriptions to their target
+	tokens  map[string]string
+	streams sync.WaitGroup
+	stopped bool
+
+	eventsTotal         *prometheus.CounterVec
+	eventsDroppedTotal  prometheus.Counter
+	subscriptionErrors  *prometheus.CounterVec
+	subscriptionsActive prometheus.GaugeFunc
+	streamReconnects    *prometheus.CounterVec
+}
+
+type subscription struct {
+	profile string
+	// ctx carries the client settings of the last scrape of the target, its TLS, proxy and module options
+	ctx context.Context
+	// token is the random Context of the push subscription, the BMC sends it back with every event
+	token string
+	// location is the path of the subscription resource on the BMC, empty until the BMC accepted it
+	location   string
+	attempted  time.Time
+	lastScrape time.Time
+	pending    bool
+	// cancel stops the server-sent events stream of the target, nil for push subscriptions
+	cancel      context.CancelFunc
+	lastEventID string
+}
+
+// NewSubscriber returns a Subscriber with its metrics registered to the default registerer
+func NewSubscriber(destination string, ttl time.Duration, sse bool) *Subscriber {
+	return newSubscriber(destination, ttl, sse, prometheus.DefaultRegisterer)
+}
+
+func newSubscriber(destination string, ttl time.Duration, sse bool, reg prometheus.Registerer) *Subscriber {
+	s := &Subscriber{
+		Destination: destination,
+		TTL:         ttl,
+		SSE:         sse,
+		Registerer:  reg,
+		subs:        make(map[string]*subscription),
+		tokens:      make(map[string]string),
+	}
+
+	s.initMetrics()
+	return s
+}
+
+// initMetrics initializes all the prometheus metrics
+func (s *Subscriber) initMetrics() {
+	s.eventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fishymetrics_events_received_total",
+		Help: "The total number of redfish events received from the subscribed targets",
+	}, []string{"target", "registry", "message_id", "severity"})
+
+	s.eventsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "fishymetrics_events_dropped_total",
+		Help: "The total number of redfish events dropped because they did not come from a subscribed target",
+	})
+
+	s.subscriptionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fishymetrics_event_subscription_errors_total",
+		Help: "The total number of failed attempts to subscribe to a target EventService",
+	}, []string{"target"})
+
+	s.subscriptionsActive = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "fishymetrics_event_subscriptions",
+		Help: "The number of targets with an active EventService subscription",
+	}, func() float64 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var active float64
+		for _, sub := range s.subs {
+			if sub.location != "" {
+				active++
+			}
+		}
+		return active
+	})
+
+	s.streamReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fishymetrics_event_stream_reconnects_total",
+		Help: "The total number of times a server-sent events stream to a target was reconnected",
+	}, []string{"target"})
+
+	s.Registerer.MustRegister(
+		s.eventsTotal,
+		s.eventsDroppedTotal,
+		s.subscriptionErrors,
+		s.subscriptionsActive,
+		s.streamReconnects,
+	)
+}
+
+// Ensure is called on every scrape of target, it subscribes to the target EventService in the background
+// when there is no subscription yet or when the current one is older than TTL. Streams are kept open
+// until the target stops being scraped. The requests to the target use the client settings carried by ctx,
+// the same ones the scrape used.
+func (s *Subscriber) Ensure(ctx context.Context, target, profile string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subs[target]
+	if !ok {
+		sub = &subscription{}
+		s.subs[target] = sub
+	}
+	sub.profile = profile
+	sub.ctx = context.WithoutCancel(exporter.WithTargetTLS(ctx, target))
+	sub.lastScrape = time.Now()
+
+	if sub.pending || sub.cancel != nil {
+		return
+	}
+	if sub.location != "" && time.Since(sub.attempted) < s.TTL {
+		return
+	}
+	if sub.location == "" && !sub.attempted.IsZero() && time.Since(sub.attempted) < subscribeRetry {
+		return
+	}
+
+	sub.pending = true
+	go s.subscribe(target, sub)
+}
+
+// subscribed reports if target has an active subscription
+func (s *Subscriber) subscribed(target string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subs[target]
+	return ok && sub.location != ""
+}
+
+// lookup returns the target of the push subscription whose Context is token
+func (s *Subscriber) lookup(token string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, ok := s.tokens[token]
+	if !ok {
+		return "", false
+	}
+	sub, ok := s.subs[target]
+	return target, ok && sub.location != "" && sub.token == token
+}
+
+func (s *Subscriber) subscribe(target string, sub *subscription) {
+	log := zap.L()
+
+	s.mu.Lock()
+	profile := sub.profile
+	ctx := sub.ctx
+	s.mu.Unlock()
+
+	client := exporter.NewHTTPClient(ctx)
+	es, err := fetchEventService(target, profile, client)
+	if err == nil && s.SSE && es.ServerSentEventUri != "" {
+		s.startStream(ctx, target, es.ServerSentEventUri, sub)
+		return
+	}
+
+	var location, token string
+	if err == nil && s.Destination == "" {
+		err = errors.New("event service does not support server-sent events")
+	}
+	if err == nil {
+		token, err = newToken()
+	}
+	if err == nil {
+		location, err = s.create(target, profile, token, es, client)
+	}
+
+	s.mu.Lock()
+	sub.pending = false
+	sub.attempted = time.Now()
+	if err == nil {
+		delete(s.tokens, sub.token)
+		s.tokens[token] = target
+		sub.token = token
+		sub.location = location
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.subscriptionErrors.WithLabelValues(target).Inc()
+		log.Error("error subscribing to event service", zap.Error(err), zap.String("target", target))
+		return
+	}
+
+	log.Info("subscribed to event service", zap.String("target", target), zap.String("subscription", location))
+}
+
+// newToken returns a random subscription Context, the events are only accepted with the Context of a
+// subscription so they can't be forged by knowing the target
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating subscription context - %s", err.Error())
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// fetchEventService returns the EventService of target
+func fetchEventService(target, profile string, client *retryablehttp.Client) (oem.EventService, error) {
+	var es oem.EventService
+
+	base, err := targetURL(target)
+	if err != nil {
+		return es, err
+	}
+
+	body, err := common.Fetch(base+"/redfish/v1/EventService/", target, profile, client)()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return es, common.ErrInvalidCredential
+		}
+		return es, fmt.Errorf("error fetching event service: %w", err)
+	}
+
+	err = json.Unmarshal(body, &es)
+	if err != nil {
+		return es, fmt.Errorf("error unmarshalling EventService struct - %s", err.Error())
+	}
+
+	if es.ServiceEnabled != nil && !*es.ServiceEnabled {
+		return es, errors.New("event service is disabled")
+	}
+
+	return es, nil
+}
+
+// create posts a new subscription with the Context token to the target EventService and deletes the previous
+// subscriptions it finds for the same destination, this covers both renewals and subscriptions left behind by
+// a previous run
+func (s *Subscriber) create(target, profile, token string, es oem.EventService, client *retryablehttp.Client) (string, error) {
+	if es.Subscriptions.URL == "" {
+		return "", errors.New("event service does not support subscriptions")
+	}
+
+	base, err := targetURL(target)
+	if err != nil {
+		return "", err
+	}
+	subscriptionsUrl := base + es.Subscriptions.URL
+	dest := oem.EventDestination{
+		Destination: s.Destination,
+		Context:     token,
+		Protocol:    "Redfish",
+	}
+
+	req, err := common.BuildRequestWithBody(http.MethodPost, subscriptionsUrl, target, dest)
+	if err != nil {
+		return "", err
+	}
+	resp, err := common.DoRequest(client, req)
+	if err != nil {
+		return "", err
+	}
+	defer common.EmptyAndCloseBody(resp)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", common.ErrInvalidCredential
+	} else if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("error creating subscription - HTTP status %d", resp.StatusCode)
+	}
+
+	// the subscription url is returned in the Location header, some BMCs only return the resource
+	location := resp.Header.Get("Location")
+	if location == "" {
+		var created oem.EventDestination
+		if err := json.NewDecoder(resp.Body).Decode(&created); err == nil {
+			location = created.Url
+		}
+	}
+	if u, err := url.Parse(location); err == nil {
+		location = u.Path
+	}
+	if location == "" {
+		return "", errors.New("subscription was created without a Location")
+	}
+
+	subscriptions, err := common.Fetch(subscriptionsUrl, target, profile, client)()
+	if err != nil {
+		// the new subscription is in place, the old ones are deleted on the next renewal
+		return location, nil
+	}
+
+	var coll oem.Collection
+	if err := json.Unmarshal(subscriptions, &coll); err != nil {
+		return location, nil
+	}
+
+	for _, member := range coll.Members {
+		if samePath(member.URL, location) {
+			continue
+		}
+
+		var existing oem.EventDestination
+		body, err := common.Fetch(base+member.URL, target, profile, client)()
+		if err != nil || json.Unmarshal(body, &existing) != nil {
+			continue
+		}
+
+		if existing.Destination == s.Destination {
+			if err := deleteSubscription(client, base+member.URL, target); err != nil {
+				zap.L().Error("error deleting previous event subscription", zap.Error(err), zap.String("target", target), zap.String("subscription", member.URL))
+			}
+		}
+	}
+
+	return location, nil
+}
+
+// Run deletes the subscriptions and closes the streams of the targets that stopped being scraped until done
+// receives, every subscription is then deleted so the BMCs stop pushing events to this instance
+func (s *Subscriber) Run(done chan bool, wg *sync.WaitGroup) {
+	log := zap.L()
+	defer wg.Done()
+
+	ticker := time.NewTicker(s.TTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			log.Info("stopping event subscriber go routine")
+			s.mu.Lock()
+			s.stopped = true
+			s.mu.Unlock()
+
+			s.unsubscribe(func(*subscription) bool { return true })
+			s.streams.Wait()
+			return
+		case <-ticker.C:
+			s.unsubscribe(func(sub *subscription) bool {
+				return time.Since(sub.lastScrape) > s.TTL
+			})
+		}
+	}
+}
+
+// unsubscribe deletes the subscriptions matching stale from the BMCs and forgets about them
+func (s *Subscriber) unsubscribe(stale func(*subscription) bool) {
+	type staleSub struct {
+		ctx              context.Context
+		target, location string
+	}
+	var remove []staleSub
+
+	s.mu.Lock()
+	for target, sub := range s.subs {
+		if sub.pending || !stale(sub) {
+			continue
+		}
+		if sub.cancel != nil {
+			sub.cancel()
+		} else if sub.location != "" {
+			remove = append(remove, staleSub{ctx: sub.ctx, target: target, location: sub.location})
+		}
+		delete(s.tokens, sub.token)
+		delete(s.subs, target)
+	}
+	s.mu.Unlock()
+
+	for _, sub := range remove {
+		base, err := targetURL(sub.target)
+		if err == nil {
+			err = deleteSubscription(exporter.NewHTTPClient(sub.ctx), base+sub.location, sub.target)
+		}
+		if err != nil {
+			zap.L().Error("error deleting event subscription", zap.Error(err), zap.String("target", sub.target), zap.String("subscription", sub.location))
+			continue
+		}
+		zap.L().Info("deleted event subscription", zap.String("target", sub.target), zap.String("subscription", sub.location))
+	}
+}
+
+func deleteSubscription(client *retryablehttp.Client, uri, target string) error {
+	req, err := common.BuildRequestWithBody(http.MethodDelete, uri, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := common.DoRequest(client, req)
+	if err != nil {
+		return err
+	}
+	defer common.EmptyAndCloseBody(resp)
+
+	// the BMC may have dropped the subscription on its own already
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// targetURL returns the scheme and host of target the same way the exporter does
+func targetURL(target string) (string, error) {
+	u, err := url.ParseRequestURI(target)
+	if err != nil || u.Host == "" {
+		u, err = url.ParseRequestURI(config.GetConfig().BMCScheme + "://" + target)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return u.Scheme + "://" + u.Host, nil
+}
+
+func samePath(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
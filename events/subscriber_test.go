@@ -0,0 +1,389 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/comcast/fishymetrics/exporter"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const subscriptionsPath = "/redfish/v1/EventService/Subscriptions/"
+
+// mockBMC is a minimal redfish EventService
+type mockBMC struct {
+	mu   sync.Mutex
+	next int
+	subs map[string]oem.EventDestination
+	// sse serves the ServerSentEventUri when set
+	sse http.HandlerFunc
+}
+
+func newMockBMC(t *testing.T, sse http.HandlerFunc) (*mockBMC, *httptest.Server) {
+	bmc := &mockBMC{subs: make(map[string]oem.EventDestination), sse: sse}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /redfish/v1/EventService/", func(w http.ResponseWriter, r *http.Request) {
+		es := oem.EventService{
+			Url:           "/redfish/v1/EventService/",
+			Subscriptions: oem.Link{URL: subscriptionsPath},
+		}
+		if bmc.sse != nil {
+			es.ServerSentEventUri = "/redfish/v1/EventService/SSE"
+		}
+		json.NewEncoder(w).Encode(es)
+	})
+	mux.HandleFunc("GET /redfish/v1/EventService/SSE", func(w http.ResponseWriter, r *http.Request) {
+		if bmc.sse == nil {
+			http.NotFound(w, r)
+			return
+		}
+		bmc.sse(w, r)
+	})
+	mux.HandleFunc("GET "+subscriptionsPath, func(w http.ResponseWriter, r *http.Request) {
+		bmc.mu.Lock()
+		defer bmc.mu.Unlock()
+
+		var members []string
+		for id := range bmc.subs {
+			members = append(members, fmt.Sprintf(`{"@odata.id": "%s"}`, id))
+		}
+		fmt.Fprintf(w, `{"@odata.id": "%s", "Members": [%s]}`, subscriptionsPath, strings.Join(members, ","))
+	})
+	mux.HandleFunc("POST "+subscriptionsPath, func(w http.ResponseWriter, r *http.Request) {
+		var dest oem.EventDestination
+		if err := json.NewDecoder(r.Body).Decode(&dest); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		location := bmc.add(dest)
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET "+subscriptionsPath+"{id}/", func(w http.ResponseWriter, r *http.Request) {
+		bmc.mu.Lock()
+		defer bmc.mu.Unlock()
+
+		dest, ok := bmc.subs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(dest)
+	})
+	mux.HandleFunc("DELETE "+subscriptionsPath+"{id}/", func(w http.ResponseWriter, r *http.Request) {
+		bmc.mu.Lock()
+		defer bmc.mu.Unlock()
+
+		delete(bmc.subs, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return bmc, server
+}
+
+func (b *mockBMC) add(dest oem.EventDestination) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.next++
+	dest.Url = fmt.Sprintf("%s%d/", subscriptionsPath, b.next)
+	b.subs[dest.Url] = dest
+	return dest.Url
+}
+
+func (b *mockBMC) destinations() []oem.EventDestination {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var dests []oem.EventDestination
+	for _, dest := range b.subs {
+		dests = append(dests, dest)
+	}
+	return dests
+}
+
+func Test_Subscriber_Subscribe(t *testing.T) {
+	assert := assert.New(t)
+	bmc, server := newMockBMC(t, nil)
+	target := server.URL
+	destination := "https://fishymetrics.example.com/events"
+
+	// left behind by a previous run and a subscription that belongs to someone else
+	leftover := bmc.add(oem.EventDestination{Destination: destination, Context: target, Protocol: "Redfish"})
+	bmc.add(oem.EventDestination{Destination: "https://other.example.com/events", Protocol: "Redfish"})
+
+	s := newSubscriber(destination, time.Hour, false, prometheus.NewRegistry())
+	s.Ensure(context.Background(), target, "")
+	assert.Eventually(func() bool { return s.subscribed(target) }, 10*time.Second, 50*time.Millisecond)
+
+	dests := bmc.destinations()
+	assert.Len(dests, 2)
+	for _, dest := range dests {
+		assert.NotEqual(leftover, dest.Url)
+	}
+
+	s.mu.Lock()
+	first := s.subs[target].location
+	token := s.subs[target].token
+	s.mu.Unlock()
+
+	// the Context is a random token mapped back to the target
+	bmc.mu.Lock()
+	assert.NotEqual(target, bmc.subs[first].Context)
+	assert.Equal(token, bmc.subs[first].Context)
+	assert.Equal(destination, bmc.subs[first].Destination)
+	bmc.mu.Unlock()
+
+	found, ok := s.lookup(token)
+	assert.True(ok)
+	assert.Equal(target, found)
+	_, ok = s.lookup(target)
+	assert.False(ok)
+
+	// a fresh subscription is left alone
+	s.Ensure(context.Background(), target, "")
+	assert.Len(bmc.destinations(), 2)
+
+	// an expired subscription is replaced
+	s.mu.Lock()
+	s.subs[target].attempted = time.Now().Add(-2 * time.Hour)
+	s.mu.Unlock()
+
+	s.Ensure(context.Background(), target, "")
+	assert.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return !s.subs[target].pending && s.subs[target].location != first
+	}, 10*time.Second, 50*time.Millisecond)
+
+	dests = bmc.destinations()
+	assert.Len(dests, 2)
+	for _, dest := range dests {
+		assert.NotEqual(first, dest.Url)
+	}
+
+	// the token of the replaced subscription is no longer accepted
+	_, ok = s.lookup(token)
+	assert.False(ok)
+}
+
+func Test_Subscriber_Subscribe_Client_Settings(t *testing.T) {
+	// the BMC is only reachable through the proxy of the scrape
+	_, proxy := newMockBMC(t, nil)
+	target := "http://bmc.invalid"
+
+	s := newSubscriber("https://fishymetrics.example.com/events", time.Hour, false, prometheus.NewRegistry())
+	s.Ensure(exporter.WithProxyURL(context.Background(), proxy.URL), target, "")
+	assert.Eventually(t, func() bool { return s.subscribed(target) }, 10*time.Second, 50*time.Millisecond)
+}
+
+func Test_Subscriber_Unsubscribe(t *testing.T) {
+	assert := assert.New(t)
+	bmc, server := newMockBMC(t, nil)
+	destination := "https://fishymetrics.example.com/events"
+
+	stale := server.URL
+	active := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	s := newSubscriber(destination, time.Hour, false, prometheus.NewRegistry())
+	s.subs[stale] = &subscription{
+		location:   bmc.add(oem.EventDestination{Destination: destination, Context: stale}),
+		lastScrape: time.Now().Add(-2 * time.Hour),
+	}
+	s.subs[active] = &subscription{
+		location:   bmc.add(oem.EventDestination{Destination: destination, Context: active}),
+		lastScrape: time.Now(),
+	}
+
+	s.unsubscribe(func(sub *subscription) bool {
+		return time.Since(sub.lastScrape) > s.TTL
+	})
+
+	assert.False(s.subscribed(stale))
+	assert.True(s.subscribed(active))
+
+	dests := bmc.destinations()
+	if assert.Len(dests, 1) {
+		assert.Equal(active, dests[0].Context)
+	}
+}
+
+func Test_Subscriber_EventsHandler(t *testing.T) {
+	assert := assert.New(t)
+	target := "10.0.0.1"
+
+	s := newSubscriber("https://fishymetrics.example.com/events", time.Hour, false, prometheus.NewRegistry())
+	s.subs[target] = &subscription{location: subscriptionsPath + "1/", token: "4f1c2b"}
+	s.tokens["4f1c2b"] = target
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name: "Subscribed Target Events",
+			body: `{
+			  "Id": "1",
+			  "Context": "4f1c2b",
+			  "Events": [
+			    {
+			      "EventId": "1",
+			      "EventType": "Alert",
+			      "MessageId": "PowerSupply.1.0.PowerSupplyFailure",
+			      "MessageSeverity": "Critical",
+			      "Message": "Power supply 2 failed",
+			      "OriginOfCondition": {"@odata.id": "/redfish/v1/Chassis/1/PowerSubsystem/PowerSupplies/2"}
+			    },
+			    {
+			      "EventId": "2",
+			      "EventType": "Alert",
+			      "MessageId": "PowerSupply.1.0.PowerSupplyFailure",
+			      "Severity": "Critical"
+			    },
+			    {
+			      "EventId": "3",
+			      "EventType": "Alert",
+			      "MessageId": "{{7*7}}.Injected",
+			      "MessageSeverity": "Catastrophic"
+			    }
+			  ]
+			}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Forged Target Context Events",
+			body: `{
+			  "Id": "2",
+			  "Context": "10.0.0.1",
+			  "Events": [{"EventId": "1", "MessageId": "Base.1.0.Success", "MessageSeverity": "OK"}]
+			}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Unknown Target Events",
+			body: `{
+			  "Id": "2",
+			  "Context": "10.0.0.2",
+			  "Events": [{"EventId": "1", "MessageId": "Base.1.0.Success", "MessageSeverity": "OK"}]
+			}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid Event Payload",
+			body:           `{"Events": `,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(test.body))
+			rr := httptest.NewRecorder()
+			s.EventsHandler(rr, req)
+			assert.Equal(test.expectedStatus, rr.Code)
+		})
+	}
+
+	assert.Equal(float64(2), testutil.ToFloat64(s.eventsTotal.WithLabelValues(target, "PowerSupply", "PowerSupply.1.0.PowerSupplyFailure", "Critical")))
+	assert.Equal(float64(1), testutil.ToFloat64(s.eventsTotal.WithLabelValues(target, "Unknown", "Unknown", "Unknown")))
+	assert.Equal(2, testutil.CollectAndCount(s.eventsTotal))
+	assert.Equal(float64(2), testutil.ToFloat64(s.eventsDroppedTotal))
+	assert.Equal(float64(1), testutil.ToFloat64(s.subscriptionsActive))
+}
+
+func Test_Subscriber_Stream(t *testing.T) {
+	assert := assert.New(t)
+	defaultBackoff := sseMinBackoff
+	sseMinBackoff = 10 * time.Millisecond
+	t.Cleanup(func() { sseMinBackoff = defaultBackoff })
+
+	var mu sync.Mutex
+	var connections int
+	var resumedFrom string
+
+	_, server := newMockBMC(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		n := connections
+		if n > 1 {
+			resumedFrom = r.Header.Get("Last-Event-ID")
+		}
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, ": keep alive\n\n")
+		fmt.Fprintf(w, "id: %d\n", n)
+		fmt.Fprintf(w, "data: {\"Id\": \"%d\",\n", n)
+		fmt.Fprintf(w, "data: \"Events\": [{\"EventId\": \"%d\", \"MessageId\": \"Memory.1.0.UncorrectableError\", \"MessageSeverity\": \"Critical\"}]}\n\n", n)
+		w.(http.Flusher).Flush()
+
+		// the first connection drops, the next ones stay open until the stream is stopped
+		if n > 1 {
+			<-r.Context().Done()
+		}
+	})
+	target := server.URL
+
+	s := newSubscriber("", time.Hour, true, prometheus.NewRegistry())
+	done := make(chan bool, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go s.Run(done, &wg)
+
+	s.Ensure(context.Background(), target, "")
+
+	counter := s.eventsTotal.WithLabelValues(target, "Memory", "Memory.1.0.UncorrectableError", "Critical")
+	assert.Eventually(func() bool { return testutil.ToFloat64(counter) == 2 }, 10*time.Second, 50*time.Millisecond)
+	assert.True(s.subscribed(target))
+	assert.Equal(float64(1), testutil.ToFloat64(s.streamReconnects.WithLabelValues(target)))
+
+	mu.Lock()
+	assert.Equal("1", resumedFrom)
+	mu.Unlock()
+
+	// stopping the subscriber closes the stream
+	done <- true
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("subscriber did not stop")
+	}
+	assert.False(s.subscribed(target))
+}
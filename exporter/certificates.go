@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/comcast/fishymetrics/oem"
+)
+
+type tlsCtxKey string
+
+const tlsRecorderKey tlsCtxKey = "tls-recorder"
+
+// tlsRecorder keeps the leaf certificate the BMC presented during the last TLS handshake
+type tlsRecorder struct {
+	mu   sync.Mutex
+	leaf *x509.Certificate
+}
+
+// withTLSRecorder returns a new context that carries a recorder for the certificate served by the BMC.
+func withTLSRecorder(ctx context.Context, rec *tlsRecorder) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, tlsRecorderKey, rec)
+}
+
+func tlsRecorderFromContext(ctx context.Context) *tlsRecorder {
+	if ctx == nil {
+		return nil
+	}
+	rec, _ := ctx.Value(tlsRecorderKey).(*tlsRecorder)
+	return rec
+}
+
+// record is used as the tls.Config VerifyConnection callback, it runs after the standard verification
+// (if enabled) so it never rejects a connection on its own
+func (r *tlsRecorder) record(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	r.leaf = cs.PeerCertificates[0]
+	r.mu.Unlock()
+	return nil
+}
+
+// lastLeaf returns the last recorded leaf certificate or nil if no TLS handshake happened
+func (r *tlsRecorder) lastLeaf() *x509.Certificate {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.leaf
+}
+
+// certificateName returns the value used for the subject and issuer labels
+func certificateName(id oem.CertificateIdentifier) string {
+	if id.CommonName != "" {
+		return id.CommonName
+	}
+	return id.Organization
+}
+
+// certificateMatches reports if the certificate resource describes the leaf certificate, the PEM encoded
+// CertificateString is compared first and the serial number or SHA-256 fingerprint are used as a fallback
+func certificateMatches(cert oem.Certificate, leaf *x509.Certificate) bool {
+	if block, _ := pem.Decode([]byte(cert.CertificateString)); block != nil {
+		if parsed, err := x509.ParseCertificate(block.Bytes); err == nil {
+			return bytes.Equal(parsed.Raw, leaf.Raw)
+		}
+	}
+
+	if cert.SerialNumber != "" {
+		serial, ok := new(big.Int).SetString(strings.ReplaceAll(cert.SerialNumber, ":", ""), 16)
+		if ok {
+			return serial.Cmp(leaf.SerialNumber) == 0
+		}
+	}
+
+	if cert.Fingerprint != "" && strings.EqualFold(cert.FingerprintHashAlgorithm, "TPM_ALG_SHA256") {
+		sum := sha256.Sum256(leaf.Raw)
+		return strings.EqualFold(strings.ReplaceAll(cert.Fingerprint, ":", ""), hex.EncodeToString(sum[:]))
+	}
+
+	return false
+}
@@ -0,0 +1,371 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/comcast/fishymetrics/pool"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	// collectors are run in registration order, the builtin components first
+	collectors []registeredCollector
+)
+
+// Collector collects the metrics of one component. The collectors are registered with RegisterCollector, full
+// scrapes run all of them and partial scrapes the ones of the requested components
+type Collector interface {
+	// Discover returns the resources of the component found on the BMC
+	Discover(d *Discovery) ([]Resource, error)
+	// Describe returns the gauges of the component keyed by name, a new set for each scrape. They are added to
+	// the DeviceMetrics of the exporter under the component name
+	Describe() map[string]*prometheus.GaugeVec
+	// Parse exports the metrics of one kind found in the body of a resource
+	Parse(e *Exporter, kind string, body []byte) error
+}
+
+// PartialOnly is implemented by the collectors that full scrapes skip
+type PartialOnly interface {
+	PartialOnly() bool
+}
+
+// Resource is a BMC resource discovered by a collector
+type Resource struct {
+	// URL is the path of the resource on the BMC
+	URL string
+	// Kinds are the kinds of metrics parsed from the resource, Parse is called once for each
+	Kinds []string
+	// Fetch replaces the GET of the resource
+	Fetch func() ([]byte, error)
+	// NoRefresh fetches the resource on every scrape regardless of the refresh interval of the component
+	NoRefresh bool
+	// Optional resources that fail to be fetched are logged and skipped instead of failing the scrape
+	Optional bool
+}
+
+type registeredCollector struct {
+	component ComponentType
+	collector Collector
+}
+
+// RegisterCollector adds the collector of a component, the component becomes valid in partial scrapes. It must be
+// called from an init function and panics when the component is already registered
+func RegisterCollector(component ComponentType, c Collector) {
+	if ValidComponents[component] {
+		panic(fmt.Sprintf("collector of component %s already registered", component))
+	}
+	ValidComponents[component] = true
+	collectors = append(collectors, registeredCollector{component: component, collector: c})
+}
+
+// ComponentNames returns the registered components in registration order
+func ComponentNames() []string {
+	names := make([]string, 0, len(collectors))
+	for _, c := range collectors {
+		names = append(names, string(c.component))
+	}
+	return names
+}
+
+// describeCollectors adds the gauges of the registered collectors to m
+func describeCollectors(m map[string]*metrics) {
+	for _, c := range collectors {
+		if gauges := c.collector.Describe(); len(gauges) > 0 {
+			g := metrics(gauges)
+			m[string(c.component)] = &g
+		}
+	}
+}
+
+// tasks returns the pool tasks of the resources of a component
+func (e *Exporter) tasks(d *Discovery, c registeredCollector, resources []Resource) []*pool.Task {
+	tasks := make([]*pool.Task, 0, len(resources))
+	for _, r := range resources {
+		url := e.url + r.URL
+		fetch := r.Fetch
+		if fetch == nil {
+			fetch = d.Fetch(r.URL)
+		}
+		if !r.NoRefresh {
+			fetch = e.withRefresh(c.component, url, fetch)
+		}
+		if r.Optional {
+			fetch = d.skipFailed(url, fetch)
+		}
+
+		var handlers []common.Handler
+		for _, kind := range r.Kinds {
+			kind, optional := kind, r.Optional
+			handlers = append(handlers, func(body []byte) error {
+				// the fetch of an optional resource failed
+				if optional && body == nil {
+					return nil
+				}
+				return c.collector.Parse(e, kind, body)
+			})
+		}
+		tasks = append(tasks, pool.NewTask(fetch, url, handlers))
+	}
+	return tasks
+}
+
+// Discovery holds the resources of a BMC shared by the collectors of a scrape, they are fetched once on first use
+type Discovery struct {
+	ctx      context.Context
+	exp      *Exporter
+	target   string
+	uri      string
+	profile  string
+	client   *retryablehttp.Client
+	excludes Excludes
+	// strict fails the scrape on the discovery errors partial scrapes skip
+	strict bool
+
+	chassis []string
+
+	managersDone bool
+	managers     []string
+	managersErr  error
+
+	managerDone bool
+	managerResp oem.Manager
+	managerErr  error
+
+	rootDone bool
+	root     oem.System
+	rootErr  error
+
+	systemsDone  bool
+	sysEndpoints SystemEndpoints
+	sysResp      oem.System
+	systemsErr   error
+
+	drivesDone bool
+	drives     DriveEndpoints
+	drivesErr  error
+}
+
+// Context returns the context of the scrape
+func (d *Discovery) Context() context.Context {
+	return d.ctx
+}
+
+// URI returns the path of the Redfish service root the resource paths start with
+func (d *Discovery) URI() string {
+	return d.uri
+}
+
+// Fetch returns the fetch of the resource at path
+func (d *Discovery) Fetch(path string) func() ([]byte, error) {
+	return common.Fetch(d.exp.url+path, d.target, d.profile, d.client)
+}
+
+// Get fetches the resource at path and unmarshals it into v
+func (d *Discovery) Get(path string, v interface{}) error {
+	body, err := d.Fetch(path)()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error unmarshalling %s - %s", path, err.Error())
+	}
+	return nil
+}
+
+// Members returns the member paths of the collection at path
+func (d *Discovery) Members(path string) ([]string, error) {
+	return getMemberUrls(d.exp.url+path, d.target, d.profile, d.client)
+}
+
+// ServiceRoot returns the service root, it links to the services of the BMC
+func (d *Discovery) ServiceRoot() (oem.System, error) {
+	if !d.rootDone {
+		d.rootDone = true
+		d.root, d.rootErr = getSystemsMetadata(d.exp.url+d.uri, d.target, d.profile, d.client)
+	}
+	return d.root, d.rootErr
+}
+
+// System returns the first system of the chassis, its fields are empty when the chassis link no system
+func (d *Discovery) System() (oem.System, error) {
+	_, sys, err := d.systems()
+	return sys, err
+}
+
+// Manager returns the path of the manager picked by the vendor of the BMC, empty when the BMC has no manager
+func (d *Discovery) Manager() (string, error) {
+	managers, err := d.managerMembers()
+	if err != nil {
+		return "", err
+	}
+	return d.exp.vendor.manager(managers), nil
+}
+
+// ManagerResource returns the manager picked by the vendor of the BMC
+func (d *Discovery) ManagerResource() (oem.Manager, error) {
+	if !d.managerDone {
+		d.managerDone = true
+		mgr, err := d.Manager()
+		switch {
+		case err != nil:
+			d.managerErr = err
+		case mgr == "":
+			d.managerErr = errors.New("no manager endpoint found")
+		default:
+			d.managerResp, d.managerErr = getManagerMetadata(d.exp.url+mgr, d.target, d.profile, d.client)
+		}
+	}
+	return d.managerResp, d.managerErr
+}
+
+func (d *Discovery) managerMembers() ([]string, error) {
+	if !d.managersDone {
+		d.managersDone = true
+		d.managers, d.managersErr = getMemberUrls(d.exp.url+d.uri+"/Managers/", d.target, d.profile, d.client)
+		if d.managersErr == nil {
+			log.Debug("manager endpoints response", zap.Strings("mgr_endpoints", d.managers), zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+		}
+	}
+	return d.managers, d.managersErr
+}
+
+// systems returns the endpoints linked by the chassis and the first system, the system sets the BIOS version,
+// serial number and hostname labels of the exporter
+func (d *Discovery) systems() (*SystemEndpoints, oem.System, error) {
+	if d.systemsDone {
+		return &d.sysEndpoints, d.sysResp, d.systemsErr
+	}
+	d.systemsDone = true
+
+	d.sysEndpoints, d.systemsErr = getSystemEndpoints(d.chassis, d.target, d.profile, d.client, d.excludes)
+	if d.systemsErr != nil {
+		log.Error("error when getting chassis endpoints", zap.Error(d.systemsErr), zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+		return &d.sysEndpoints, d.sysResp, d.systemsErr
+	}
+
+	log.Debug("systems endpoints response", zap.Strings("systems_endpoints", d.sysEndpoints.systems),
+		zap.Strings("storage_ctrl_endpoints", d.sysEndpoints.storageController),
+		zap.Strings("drives_endpoints", d.sysEndpoints.drives),
+		zap.Strings("power_endpoints", d.sysEndpoints.power),
+		zap.Strings("power_subsystem_endpoints", d.sysEndpoints.powerSubsystem),
+		zap.Strings("environment_endpoints", d.sysEndpoints.environment),
+		zap.Strings("thermal_endpoints", d.sysEndpoints.thermal),
+		zap.Strings("thermal_subsystem_endpoints", d.sysEndpoints.thermalSubsystem),
+		zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+
+	if len(d.sysEndpoints.systems) > 0 {
+		// call /redfish/v1/Systems/XXXXX/ for BIOS, Serial number
+		// TODO: do not assume 1 systems endpoint
+		d.sysResp, d.systemsErr = getSystemsMetadata(d.exp.url+d.sysEndpoints.systems[0], d.target, d.profile, d.client)
+		if d.systemsErr != nil {
+			log.Error("error when getting BIOS version", zap.Error(d.systemsErr), zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+			return &d.sysEndpoints, d.sysResp, d.systemsErr
+		}
+		d.exp.biosVersion = d.sysResp.BiosVersion
+		d.exp.ChassisSerialNumber = strings.TrimRight(d.sysResp.SerialNumber, " ")
+		d.exp.systemHostname = d.sysResp.SystemHostname
+
+		if d.sysResp.EnvironmentMetrics.URL != "" {
+			url := appendSlash(d.sysResp.EnvironmentMetrics.URL)
+			if checkUnique(d.sysEndpoints.environment, url) {
+				d.sysEndpoints.environment = append(d.sysEndpoints.environment, url)
+			}
+		}
+	}
+
+	return &d.sysEndpoints, d.sysResp, nil
+}
+
+// driveEndpoints returns the controllers and drives found under the SmartStorage or Storage resources of the system
+func (d *Discovery) driveEndpoints() (DriveEndpoints, error) {
+	if d.drivesDone {
+		return d.drives, d.drivesErr
+	}
+	d.drivesDone = true
+
+	sys, sysResp, err := d.systems()
+	if err != nil {
+		d.drivesErr = err
+		return d.drives, err
+	}
+
+	// check for SmartStorage endpoint from either Hp or Hpe
+	// skip if SmartStorage URL is not present
+	var ss = GetSmartStorageURL(sysResp)
+	if ss != "" {
+		d.drives, d.drivesErr = getAllDriveEndpoints(d.ctx, d.exp.url, d.exp.url+ss, d.target, d.profile, d.client, d.excludes, d.exp.query)
+		if d.drivesErr != nil {
+			log.Error("error when getting drive endpoints", zap.Error(d.drivesErr), zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+			return d.drives, d.drivesErr
+		}
+	}
+
+	if (len(sys.storageController) == 0 && ss == "") || (len(sys.drives) == 0 && len(d.drives.physicalDriveURLs) == 0) {
+		if sysResp.Storage.URL != "" {
+			url := appendSlash(sysResp.Storage.URL)
+			d.drives, d.drivesErr = getAllDriveEndpoints(d.ctx, d.exp.url, d.exp.url+url, d.target, d.profile, d.client, d.excludes, d.exp.query)
+			if d.drivesErr != nil {
+				log.Error("error when getting drive endpoints", zap.Error(d.drivesErr), zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+				return d.drives, d.drivesErr
+			}
+		}
+	}
+
+	log.Debug("drive endpoints response", zap.Strings("array_controller_endpoints", d.drives.arrayControllerURLs),
+		zap.Strings("logical_drive_endpoints", d.drives.logicalDriveURLs),
+		zap.Strings("physical_drive_endpoints", d.drives.physicalDriveURLs),
+		zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+
+	return d.drives, nil
+}
+
+// logError logs a discovery error the scrape carries on after
+func (d *Discovery) logError(msg string, err error) {
+	log.Error(msg, zap.Error(err), zap.Any("trace_id", d.ctx.Value(logging.TraceIDKey("traceID"))))
+}
+
+// skipFailed returns fetch with its errors logged and replaced by a nil body, the handlers of the optional
+// resources skip it
+func (d *Discovery) skipFailed(url string, fetch func() ([]byte, error)) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		body, err := fetch()
+		if err != nil {
+			d.logError("error when fetching optional resource "+url, err)
+			return nil, nil
+		}
+		return body, nil
+	}
+}
+
+// skip logs a discovery error, full scrapes fail on it while partial scrapes skip the resources it hides
+func (d *Discovery) skip(msg string, err error) error {
+	d.logError(msg, err)
+	if d.strict {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,564 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	RegisterCollector(ComponentThermal, &builtinCollector{discover: discoverThermal})
+	RegisterCollector(ComponentPower, &builtinCollector{discover: discoverPower})
+	RegisterCollector(ComponentMemory, &builtinCollector{discover: discoverMemory})
+	RegisterCollector(ComponentProcessor, &builtinCollector{discover: discoverProcessor})
+	RegisterCollector(ComponentDrives, &builtinCollector{discover: discoverDrives})
+	RegisterCollector(ComponentStorageController, &builtinCollector{discover: discoverStorageController})
+	RegisterCollector(ComponentFirmware, &builtinCollector{discover: discoverFirmware})
+	RegisterCollector(ComponentSystem, &builtinCollector{discover: discoverSystem})
+	// the certificate expiry is alerted on, so the default scrapes collect it, a failed certificate is skipped
+	RegisterCollector(ComponentCertificates, &builtinCollector{discover: discoverCertificates})
+	// the security posture, logs and telemetry reports are only collected by partial scrapes, they rarely change
+	// or take many requests
+	RegisterCollector(ComponentSecurity, &builtinCollector{discover: discoverSecurity, partialOnly: true})
+	RegisterCollector(ComponentLogs, &builtinCollector{discover: discoverLogs, partialOnly: true})
+	RegisterCollector(ComponentTelemetry, &builtinCollector{discover: discoverTelemetry, partialOnly: true})
+	// the metric mappings of the config file, it collects nothing without them
+	RegisterCollector(ComponentMappings, &mappingCollector{})
+}
+
+// builtinCollector is the collector of a builtin component, its metrics are in the groups of NewDeviceMetrics
+// since several components share them
+type builtinCollector struct {
+	discover    func(d *Discovery) ([]Resource, error)
+	partialOnly bool
+}
+
+func (c *builtinCollector) Discover(d *Discovery) ([]Resource, error) {
+	return c.discover(d)
+}
+
+func (c *builtinCollector) Describe() map[string]*prometheus.GaugeVec {
+	return nil
+}
+
+func (c *builtinCollector) Parse(e *Exporter, kind string, body []byte) error {
+	parse, ok := parsers[kind]
+	if !ok {
+		return fmt.Errorf("unknown metric kind %s", kind)
+	}
+	return parse(e, body)
+}
+
+func (c *builtinCollector) PartialOnly() bool {
+	return c.partialOnly
+}
+
+// member returns the fetch of a collection member that only requests the properties of kind when the BMC supports it
+func (d *Discovery) member(url, kind string) func() ([]byte, error) {
+	return d.exp.query.member(d.exp.url+url, d.target, d.profile, d.client, kind)
+}
+
+// revalidated returns the fetch of a slow-changing resource, its response is kept and revalidated with its ETag
+func (d *Discovery) revalidated(url string) func() ([]byte, error) {
+	return common.FetchRevalidated(d.exp.url+url, d.target, d.profile, d.client)
+}
+
+func discoverSystem(d *Discovery) ([]Resource, error) {
+	sys, _, err := d.systems()
+	if err != nil || len(sys.systems) == 0 {
+		return nil, err
+	}
+
+	// call /redfish/v1/Systems/XXXXX/ for memory summary and smart storage batteries
+	return []Resource{{URL: sys.systems[0], Kinds: []string{MEMORY_SUMMARY, STORAGEBATTERY, SYSTEM}}}, nil
+}
+
+func discoverPower(d *Discovery) ([]Resource, error) {
+	sys, _, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, url := range sys.power {
+		resources = append(resources, Resource{URL: url, Kinds: []string{POWER}})
+	}
+
+	// power subsystem, the power supplies are only walked when the legacy Power endpoint is missing
+	// so they are not reported twice, or when there is no EnvironmentMetrics to read energy from
+	for _, url := range sys.powerSubsystem {
+		if len(sys.power) == 0 {
+			resources = append(resources, Resource{URL: url, Kinds: []string{POWERSUBSYSTEM, POWER_REDUNDANCY}})
+		} else {
+			resources = append(resources, Resource{URL: url, Kinds: []string{POWERSUBSYSTEM}})
+		}
+		if len(sys.power) > 0 && len(sys.environment) > 0 {
+			continue
+		}
+		psuEndpoints, err := getPowerSupplyEndpoints(d.exp.url, d.exp.url+url, d.target, d.profile, d.client)
+		if err != nil {
+			if err := d.skip("error when getting power supply endpoints", err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(sys.power) == 0 {
+			for _, psu := range psuEndpoints {
+				resources = append(resources, Resource{URL: psu, Kinds: []string{POWERSUPPLY}})
+			}
+		}
+		if len(sys.environment) == 0 {
+			psuMetrics, err := getPowerSupplyMetricsEndpoints(d.exp.url, psuEndpoints, d.target, d.profile, d.client)
+			if err != nil {
+				if err := d.skip("error when getting power supply metrics endpoints", err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for _, m := range psuMetrics {
+				resources = append(resources, Resource{URL: m, Kinds: []string{ENERGY}})
+			}
+		}
+	}
+
+	// energy
+	for _, url := range sys.environment {
+		resources = append(resources, Resource{URL: url, Kinds: []string{ENERGY}})
+	}
+
+	return resources, nil
+}
+
+func discoverThermal(d *Discovery) ([]Resource, error) {
+	sys, _, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for _, url := range sys.thermal {
+		resources = append(resources, Resource{URL: url, Kinds: []string{THERMAL}})
+	}
+
+	// thermal subsystem, only used when the legacy Thermal endpoint is missing so fans are not reported twice
+	if len(sys.thermal) == 0 {
+		for _, url := range sys.thermalSubsystem {
+			resources = append(resources, Resource{URL: url, Kinds: []string{THERMALSUBSYSTEM}})
+			fanEndpoints, err := getFanEndpoints(d.exp.url, d.exp.url+url, d.target, d.profile, d.client)
+			if err != nil {
+				if err := d.skip("error when getting fan endpoints", err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for _, fan := range fanEndpoints {
+				resources = append(resources, Resource{URL: fan, Kinds: []string{FAN}})
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+func discoverMemory(d *Discovery) ([]Resource, error) {
+	_, sysResp, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+
+	// DIMM endpoints array
+	var systemMemoryEndpoint = GetMemoryURL(sysResp)
+	if systemMemoryEndpoint == "" {
+		return nil, nil
+	}
+	dimms, err := getDIMMEndpoints(d.exp.url+systemMemoryEndpoint, d.target, d.profile, d.client, d.exp.query)
+	if err != nil {
+		return nil, d.skip("error when getting DIMM endpoints", err)
+	}
+
+	var resources []Resource
+	for _, dimm := range dimms.Members {
+		resources = append(resources, Resource{URL: dimm.URL, Kinds: []string{MEMORY}, Fetch: d.member(dimm.URL, MEMORY)})
+	}
+	return resources, nil
+}
+
+func discoverProcessor(d *Discovery) ([]Resource, error) {
+	sys, _, err := d.systems()
+	if err != nil || len(sys.systems) == 0 {
+		return nil, err
+	}
+
+	// CPU processor metrics
+	processors, err := getProcessorEndpoints(d.exp.url+sys.systems[0]+"Processors/", d.target, d.profile, d.client, d.exp.query)
+	if err != nil {
+		return nil, d.skip("error when getting Processors endpoints", err)
+	}
+
+	var resources []Resource
+	for _, processor := range processors.Members {
+		resources = append(resources, Resource{URL: processor.URL, Kinds: []string{PROCESSOR}, Fetch: d.member(processor.URL, PROCESSOR)})
+	}
+	return resources, nil
+}
+
+func discoverStorageController(d *Discovery) ([]Resource, error) {
+	sys, _, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+	drives, err := d.driveEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	// System Endpoints
+	for _, url := range sys.storageController {
+		resources = append(resources, Resource{URL: url, Kinds: []string{STORAGE_CONTROLLER}, Fetch: d.member(url, STORAGE_CONTROLLER)})
+	}
+	// SmartStorage or Storage endpoints
+	for _, url := range drives.arrayControllerURLs {
+		resources = append(resources, Resource{URL: url, Kinds: []string{STORAGE_CONTROLLER}, Fetch: d.member(url, STORAGE_CONTROLLER)})
+	}
+	return resources, nil
+}
+
+func discoverDrives(d *Discovery) ([]Resource, error) {
+	sys, _, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+	drives, err := d.driveEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	virtualDrives, err := d.virtualDrives(sys)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	// virtual drives
+	for _, url := range virtualDrives {
+		resources = append(resources, Resource{URL: url, Kinds: []string{LOGICALDRIVE}, Fetch: d.member(url, LOGICALDRIVE)})
+	}
+	// Logical drives
+	for _, url := range drives.logicalDriveURLs {
+		resources = append(resources, Resource{URL: url, Kinds: []string{LOGICALDRIVE}, Fetch: d.member(url, LOGICALDRIVE)})
+	}
+	// drives from this list could either be NVMe or physical SAS/SATA
+	for _, url := range sys.drives {
+		resources = append(resources, Resource{URL: url, Kinds: []string{UNKNOWN_DRIVE}, Fetch: d.member(url, UNKNOWN_DRIVE)})
+	}
+	// SmartStorage or Storage endpoints
+	for _, url := range drives.physicalDriveURLs {
+		resources = append(resources, Resource{URL: url, Kinds: []string{UNKNOWN_DRIVE}, Fetch: d.member(url, UNKNOWN_DRIVE)})
+	}
+	return resources, nil
+}
+
+// virtualDrives returns the virtual drives of the volumes found in the storage controllers, newer servers hold
+// them in the storage controller volumes. The volumes are only walked when a drive exclude is set
+func (d *Discovery) virtualDrives(sys *SystemEndpoints) ([]string, error) {
+	var volumes, virtualDrives []string
+
+	reg, ok := d.excludes["drive"]
+	if !ok {
+		return nil, nil
+	}
+
+	for _, controller := range sys.storageController {
+		controllerOutput, err := getSystemsMetadata(d.exp.url+controller, d.target, d.profile, d.client)
+		if err != nil {
+			return nil, d.skip("error when getting storage controller metadata", err)
+		}
+		for _, volume := range controllerOutput.Volumes.LinksURLSlice {
+			url := appendSlash(volume)
+			if !reg.(*regexp.Regexp).MatchString(url) && checkUnique(volumes, url) {
+				volumes = append(volumes, url)
+			}
+		}
+	}
+
+	for _, volume := range volumes {
+		members, err := d.Members(volume)
+		if err != nil {
+			return nil, d.skip("error when getting virtual drive member urls", err)
+		}
+		for _, virtualDrive := range members {
+			if strings.Contains(virtualDrive, "Virtual") {
+				url := appendSlash(virtualDrive)
+				if checkUnique(virtualDrives, url) {
+					virtualDrives = append(virtualDrives, url)
+				}
+			}
+		}
+	}
+
+	return virtualDrives, nil
+}
+
+func discoverFirmware(d *Discovery) ([]Resource, error) {
+	var resources []Resource
+
+	mgr, err := d.Manager()
+	if err != nil {
+		d.logError("error when getting manager endpoint", err)
+		return nil, err
+	}
+	if mgr != "" {
+		// call /redfish/v1/Managers/XXX/ for firmware version, ilo self test and manager health metrics
+		resources = append(resources, Resource{
+			URL:       mgr,
+			Kinds:     []string{FIRMWARE, ILOSELFTEST, MANAGER},
+			Fetch:     fetchWithTime(d.Fetch(mgr), &d.exp.managerFetchedAt),
+			NoRefresh: true,
+		})
+
+		// ManagerDiagnosticData is only present on newer firmware so failing to find it does not fail the scrape
+		mgrResp, err := d.ManagerResource()
+		if err != nil {
+			d.logError("error when getting manager metadata", err)
+		} else if mgrResp.ManagerDiagnosticData.URL != "" {
+			resources = append(resources, Resource{URL: appendSlash(mgrResp.ManagerDiagnosticData.URL), Kinds: []string{MANAGERDIAGNOSTIC}, NoRefresh: true})
+		}
+	}
+
+	//Firmware Inventory - Try the iLo 4 firmware inventory endpoints using sysEndpoints.systems URL
+	// call /redfish/v1/Systems/XXXX/FirmwareInventory/
+	_, sysResp, err := d.systems()
+	if err != nil {
+		if err := d.skip("error when getting system metadata", err); err != nil {
+			return nil, err
+		}
+	}
+	if systemFML := GetFirmwareInventoryURL(sysResp); systemFML != "" {
+		return append(resources, Resource{URL: systemFML, Kinds: []string{FIRMWAREINVENTORY}, Fetch: d.revalidated(systemFML)}), nil
+	}
+
+	// Check for /redfish/v1/Managers/XXXX/UpdateService/ for firmware inventory URL
+	rootComponents, err := d.ServiceRoot()
+	if err != nil {
+		return resources, d.skip("error when getting root components metadata", err)
+	}
+	if rootComponents.UpdateService.URL == "" {
+		return resources, nil
+	}
+	var updateService oem.System
+	if err := d.Get(rootComponents.UpdateService.URL, &updateService); err != nil {
+		return resources, d.skip("error when getting update service metadata", err)
+	}
+
+	var firmwareInventoryEndpoints []string
+	if len(updateService.FirmwareInventory.LinksURLSlice) == 1 {
+		firmwareInventoryEndpoints, err = d.Members(updateService.FirmwareInventory.LinksURLSlice[0])
+		if err != nil {
+			d.logError("error when getting firmware inventory endpoints", err)
+			return nil, err
+		}
+	} else if len(updateService.FirmwareInventory.LinksURLSlice) > 1 {
+		firmwareInventoryEndpoints = updateService.FirmwareInventory.LinksURLSlice
+	}
+
+	if len(firmwareInventoryEndpoints) < 75 {
+		for _, fwEp := range firmwareInventoryEndpoints {
+			// this list can potentially be large and cause scrapes to take a long time
+			// see the '--collector.firmware.modules-exclude' config in the README for more information
+			if reg, ok := d.excludes["firmware"]; ok {
+				if !reg.(*regexp.Regexp).MatchString(fwEp) {
+					resources = append(resources, Resource{URL: fwEp, Kinds: []string{FIRMWAREINVENTORY}, Fetch: d.revalidated(fwEp)})
+				}
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverSecurity returns the manager network protocols, the account service and its accounts, the trusted
+// modules and Secure Boot of the system
+func discoverSecurity(d *Discovery) ([]Resource, error) {
+	var resources []Resource
+
+	mgr, err := d.Manager()
+	if err != nil {
+		d.logError("error when getting manager endpoint", err)
+		return nil, err
+	}
+	if mgr != "" {
+		mgrResp, err := d.ManagerResource()
+		if err != nil {
+			d.logError("error when getting manager metadata", err)
+		} else if mgrResp.NetworkProtocol.URL != "" {
+			resources = append(resources, Resource{URL: appendSlash(mgrResp.NetworkProtocol.URL), Kinds: []string{NETWORKPROTOCOL}})
+		}
+	}
+
+	rootComponents, err := d.ServiceRoot()
+	if err != nil {
+		d.logError("error when getting service root", err)
+	} else if rootComponents.AccountService.URL != "" {
+		accountServiceEndpoint := appendSlash(rootComponents.AccountService.URL)
+		accountEndpoints, err := getAccountEndpoints(d.exp.url, d.exp.url+accountServiceEndpoint, d.target, d.profile, d.client)
+		if err != nil {
+			d.logError("error when getting account endpoints", err)
+		} else {
+			// the account service task has to come first so the enabled account gauges are initialized
+			resources = append(resources, Resource{URL: accountServiceEndpoint, Kinds: []string{ACCOUNTSERVICE}})
+			for _, account := range accountEndpoints {
+				resources = append(resources, Resource{URL: account, Kinds: []string{ACCOUNT}})
+			}
+		}
+	}
+
+	sys, sysResp, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+	if len(sys.systems) > 0 {
+		resources = append(resources, Resource{URL: sys.systems[0], Kinds: []string{TRUSTEDMODULES}})
+		if sysResp.SecureBoot.URL != "" {
+			resources = append(resources, Resource{URL: appendSlash(sysResp.SecureBoot.URL), Kinds: []string{SECUREBOOT}})
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverCertificates returns the manager HTTPS certificates and the certificates of the CertificateService
+// locations, each certificate once. They are optional so a certificate that can't be fetched doesn't fail the scrape
+func discoverCertificates(d *Discovery) ([]Resource, error) {
+	var resources []Resource
+	var certEndpoints []string
+
+	mgr, err := d.Manager()
+	if err != nil {
+		d.logError("error when getting manager endpoint", err)
+		return nil, err
+	}
+	if mgr != "" {
+		mgrResp, err := d.ManagerResource()
+		if err != nil {
+			d.logError("error when getting manager metadata", err)
+		} else if mgrResp.NetworkProtocol.URL != "" {
+			httpsCertsEndpoint, err := getHTTPSCertificatesEndpoint(d.exp.url+appendSlash(mgrResp.NetworkProtocol.URL), d.target, d.profile, d.client)
+			if err != nil {
+				d.logError("error when getting https certificates endpoint", err)
+			} else if httpsCertsEndpoint != "" {
+				httpsCertEndpoints, err := d.Members(httpsCertsEndpoint)
+				if err != nil {
+					d.logError("error when getting https certificate endpoints", err)
+				} else {
+					// the collection task exports the served certificate before the members are compared to it
+					resources = append(resources, Resource{URL: httpsCertsEndpoint, Kinds: []string{HTTPSCERTIFICATES}, Optional: true})
+					for _, cert := range httpsCertEndpoints {
+						certEndpoints = append(certEndpoints, cert)
+						resources = append(resources, Resource{URL: cert, Kinds: []string{HTTPSCERTIFICATE}, Optional: true})
+					}
+				}
+			}
+		}
+	}
+
+	rootComponents, err := d.ServiceRoot()
+	if err != nil {
+		d.logError("error when getting service root", err)
+	} else if rootComponents.CertificateService.URL != "" {
+		locations, err := getCertificateLocationEndpoints(d.exp.url, d.exp.url+appendSlash(rootComponents.CertificateService.URL), d.target, d.profile, d.client)
+		if err != nil {
+			d.logError("error when getting certificate locations", err)
+		}
+		for _, cert := range locations {
+			// the HTTPS certificates are usually listed in both places
+			if checkUnique(certEndpoints, cert) {
+				certEndpoints = append(certEndpoints, cert)
+				resources = append(resources, Resource{URL: cert, Kinds: []string{CERTIFICATE}, Optional: true})
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverTelemetry returns the TelemetryService metric reports, a handful of reports carry the readings of many
+// resources
+func discoverTelemetry(d *Discovery) ([]Resource, error) {
+	var resources []Resource
+
+	rootComponents, err := d.ServiceRoot()
+	if err != nil {
+		d.logError("error when getting service root", err)
+	} else if rootComponents.TelemetryService.URL != "" {
+		reports, err := getMetricReportEndpoints(d.exp.url, d.exp.url+appendSlash(rootComponents.TelemetryService.URL), d.target, d.profile, d.client)
+		if err != nil {
+			d.logError("error when getting metric report endpoints", err)
+		}
+		for _, report := range reports {
+			resources = append(resources, Resource{URL: report, Kinds: []string{TELEMETRY}})
+		}
+	}
+
+	return resources, nil
+}
+
+// discoverLogs returns the log service entries, the manager SEL and the system logs such as the HPE IML
+func discoverLogs(d *Discovery) ([]Resource, error) {
+	var resources []Resource
+	var logServices []string
+
+	mgr, err := d.Manager()
+	if err != nil {
+		d.logError("error when getting manager endpoint", err)
+		return nil, err
+	}
+	if mgr != "" {
+		mgrResp, err := d.ManagerResource()
+		if err != nil {
+			d.logError("error when getting manager metadata", err)
+		} else if mgrResp.LogServices.URL != "" {
+			logServices = append(logServices, appendSlash(mgrResp.LogServices.URL))
+		}
+	}
+
+	_, sysResp, err := d.systems()
+	if err != nil {
+		return nil, err
+	}
+	if sysResp.LogServices.URL != "" {
+		logServices = append(logServices, appendSlash(sysResp.LogServices.URL))
+	}
+
+	for _, logService := range logServices {
+		entriesEndpoints, err := getLogServiceEntriesEndpoints(d.exp.url, d.exp.url+logService, d.target, d.profile, d.client)
+		if err != nil {
+			d.logError("error when getting log service endpoints", err)
+			continue
+		}
+
+		for _, entries := range entriesEndpoints {
+			resources = append(resources, Resource{URL: entries, Kinds: []string{LOGENTRIES}, Fetch: fetchLogEntries(d.exp.url, entries, logServiceKey(d.exp.host, entries), d.target, d.profile, d.client, d.exp.query.skip())})
+		}
+	}
+
+	return resources, nil
+}
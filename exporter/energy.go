@@ -0,0 +1,105 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// joulesPerKWh is used to convert the kWh readings reported by the BMC into joules
+	joulesPerKWh = 3.6e6
+	// energyCounterTTL is how long the reading of a sensor that is no longer scraped is kept, the counter of
+	// a sensor scraped again after that restarts from its reading
+	energyCounterTTL = 24 * time.Hour
+)
+
+var (
+	energyCounters = energyStore{
+		counters: make(map[energyKey]*energyCounter),
+	}
+)
+
+// energyStore keeps the last energy reading of every sensor across scrapes, a new Exporter
+// is created for each scrape so this state has to live outside of it
+type energyStore struct {
+	mu        sync.Mutex
+	counters  map[energyKey]*energyCounter
+	lastPrune time.Time
+}
+
+// energyKey identifies an energy sensor of a target
+type energyKey struct {
+	host   string
+	url    string
+	sensor string
+}
+
+type energyCounter struct {
+	lastReading float64
+	offset      float64
+	resetTime   string
+	lastSeen    time.Time
+}
+
+// observe records a new kWh reading for the sensor identified by key and returns the
+// monotonically increasing total. If the BMC resets the sensor, either the reading goes
+// backwards or SensorResetTime changes, the last reading is folded into the offset so the
+// exported counter never decreases.
+func (s *energyStore) observe(key energyKey, reading float64, resetTime string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+
+	c, ok := s.counters[key]
+	if !ok {
+		c = &energyCounter{
+			lastReading: reading,
+			resetTime:   resetTime,
+			lastSeen:    now,
+		}
+		s.counters[key] = c
+		return reading
+	}
+	c.lastSeen = now
+
+	if reading < c.lastReading || (resetTime != "" && c.resetTime != "" && resetTime != c.resetTime) {
+		c.offset += c.lastReading
+	}
+
+	c.lastReading = reading
+	c.resetTime = resetTime
+
+	return c.offset + reading
+}
+
+// prune drops the sensors not observed for energyCounterTTL, it walks the counters at most once an hour
+func (s *energyStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < time.Hour {
+		return
+	}
+	s.lastPrune = now
+
+	for key, c := range s.counters {
+		if now.Sub(c.lastSeen) > energyCounterTTL {
+			delete(s.counters, key)
+		}
+	}
+}
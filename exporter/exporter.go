@@ -21,15 +21,13 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strconv"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/comcast/fishymetrics/common"
 	"github.com/comcast/fishymetrics/config"
 	"github.com/comcast/fishymetrics/middleware/logging"
-	"github.com/comcast/fishymetrics/oem"
 	"github.com/comcast/fishymetrics/pool"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -55,6 +53,8 @@ const (
 	MEMORY = "MemoryMetrics"
 	// MEMORY_SUMMARY represents the memory metric endpoints
 	MEMORY_SUMMARY = "MemorySummaryMetrics"
+	// SYSTEM represents the host power, boot and health state from the system endpoint
+	SYSTEM = "SystemMetrics"
 	// FIRMWARE represents the firmware metric endpoints
 	FIRMWARE = "FirmwareMetrics"
 	// PROCESSOR represents the processor metric endpoints
@@ -63,8 +63,44 @@ const (
 	STORAGEBATTERY = "StorBatteryMetrics"
 	// ILOSELFTEST represents the processor metric endpoints
 	ILOSELFTEST = "iloSelfTestMetrics"
+	// MANAGER represents the manager health, uptime and clock metric endpoint
+	MANAGER = "ManagerMetrics"
+	// MANAGERDIAGNOSTIC represents the ManagerDiagnosticData metric endpoint
+	MANAGERDIAGNOSTIC = "ManagerDiagnosticMetrics"
+	// NETWORKPROTOCOL represents the manager NetworkProtocol metric endpoint
+	NETWORKPROTOCOL = "NetworkProtocolMetrics"
+	// ACCOUNTSERVICE represents the AccountService metric endpoint
+	ACCOUNTSERVICE = "AccountServiceMetrics"
+	// ACCOUNT represents the AccountService account metric endpoints
+	ACCOUNT = "AccountMetrics"
+	// SECUREBOOT represents the system SecureBoot metric endpoint
+	SECUREBOOT = "SecureBootMetrics"
+	// TRUSTEDMODULES represents the system TrustedModules metrics
+	TRUSTEDMODULES = "TrustedModulesMetrics"
+	// CERTIFICATE represents the CertificateLocations certificate metric endpoints
+	CERTIFICATE = "CertificateMetrics"
+	// HTTPSCERTIFICATES represents the manager HTTPS certificate collection endpoint
+	HTTPSCERTIFICATES = "HTTPSCertificatesMetrics"
+	// HTTPSCERTIFICATE represents the manager HTTPS certificate metric endpoints
+	HTTPSCERTIFICATE = "HTTPSCertificateMetrics"
+	// TELEMETRY represents the TelemetryService metric report endpoints
+	TELEMETRY = "TelemetryMetrics"
+	// LOGENTRIES represents the LogService Entries collection endpoints
+	LOGENTRIES = "LogEntriesMetrics"
 	// FIRMWAREINVENTORY represents the component firmware metric endpoints
 	FIRMWAREINVENTORY = "FirmwareInventoryMetrics"
+	// POWERSUBSYSTEM represents the newer PowerSubsystem metric endpoint
+	POWERSUBSYSTEM = "PowerSubsystemMetrics"
+	// POWERSUPPLY represents the PowerSubsystem power supply metric endpoints
+	POWERSUPPLY = "PowerSupplyMetrics"
+	// ENERGY represents the EnvironmentMetrics and PowerSupplyMetrics energy endpoints
+	ENERGY = "EnergyMetrics"
+	// POWER_REDUNDANCY represents the PowerSubsystem power supply redundancy groups
+	POWER_REDUNDANCY = "PowerRedundancyMetrics"
+	// THERMALSUBSYSTEM represents the newer ThermalSubsystem metric endpoint
+	THERMALSUBSYSTEM = "ThermalSubsystemMetrics"
+	// FAN represents the ThermalSubsystem fan metric endpoints
+	FAN = "FanMetrics"
 	// OK is a string representation of the float 1.0 for device status
 	OK = 1.0
 	// BAD is a string representation of the float 0.0 for device status
@@ -91,6 +127,15 @@ type Exporter struct {
 	systemHostname      string
 	ChassisSerialNumber string
 	DeviceMetrics       *map[string]*metrics
+	DeviceCounters      *map[string]*counterMetrics
+	redundancyGroups    []redundancyGroup
+	managerFetchedAt    time.Time
+	tlsRecorder         *tlsRecorder
+	query               *queryOptions
+	vendor              *Vendor
+	mappings            map[string]*MetricMapping
+	refreshed           map[ComponentType]bool
+	memberHealth        map[string]bool
 	Model               string
 }
 
@@ -99,6 +144,9 @@ type SystemEndpoints struct {
 	drives            []string
 	systems           []string
 	power             []string
+	powerSubsystem    []string
+	environment       []string
+	thermalSubsystem  []string
 	thermal           []string
 	volumes           []string
 	virtualDrives     []string
@@ -118,18 +166,26 @@ type Plugin interface {
 
 // NewExporter returns an initialized Exporter for a redfish API capable device.
 func NewExporter(ctx context.Context, target, uri, profile, model string, excludes Excludes, plugins ...Plugin) (*Exporter, error) {
+	return newExporter(ctx, target, uri, profile, model, excludes, nil, plugins...)
+}
+
+// newExporter runs the registered collectors of components, all of them but the partial only ones when components
+// is empty. Full scrapes fail when the BMC has no system or manager, or on the discovery errors partial scrapes skip
+func newExporter(ctx context.Context, target, uri, profile, model string, excludes Excludes, components []ComponentType, plugins ...Plugin) (*Exporter, error) {
 	var u *url.URL
 	var tasks []*pool.Task
 	var exp = Exporter{
-		ctx:           ctx,
-		credProfile:   profile,
-		DeviceMetrics: NewDeviceMetrics(),
-		Model:         model,
+		ctx:            ctx,
+		credProfile:    profile,
+		DeviceMetrics:  NewDeviceMetrics(),
+		DeviceCounters: NewDeviceCounters(),
+		Model:          model,
+		tlsRecorder:    &tlsRecorder{},
 	}
 
 	log = zap.L()
 
-	retryClient := NewHTTPClient(ctx)
+	retryClient := NewHTTPClient(withTLSRecorder(ctx, exp.tlsRecorder))
 	retryClient.RequestLogHook = func(l retryablehttp.Logger, r *http.Request, i int) {
 		retryCount := i
 		if retryCount > 0 {
@@ -144,7 +200,7 @@ func NewExporter(ctx context.Context, target, uri, profile, model string, exclud
 	if err != nil || u.Host == "" {
 		u, err = url.ParseRequestURI(config.GetConfig().BMCScheme + "://" + target)
 		if err != nil {
-			log.Error("error parsing target param", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+			log.Error("error parsing target param", zap.Error(err), zap.String("target", target), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 			return &exp, err
 		}
 	}
@@ -159,6 +215,14 @@ func NewExporter(ctx context.Context, target, uri, profile, model string, exclud
 		return &exp, nil
 	}
 
+	// authenticate with a session token instead of basic auth when the scrape asks for it
+	if scrapeOptionsFromContext(ctx).SessionAuth {
+		if err := common.Sessions.Login(exp.url+uri+"/SessionService/Sessions", target, retryClient); err != nil {
+			log.Error("error creating redfish session", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+			return nil, err
+		}
+	}
+
 	chassisEndpoints, err := getMemberUrls(exp.url+uri+"/Chassis/", target, profile, retryClient)
 	if err != nil {
 		log.Error("error when getting chassis url", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
@@ -180,269 +244,89 @@ func NewExporter(ctx context.Context, target, uri, profile, model string, exclud
 
 	log.Debug("chassis endpoints response", zap.Strings("chassis_endpoints", chassisEndpoints), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 
-	mgrEndpoints, err := getMemberUrls(exp.url+uri+"/Managers/", target, profile, retryClient)
-	if err != nil {
-		log.Error("error when getting manager endpoint", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-		return nil, err
-	}
-
-	log.Debug("manager endpoints response", zap.Strings("mgr_endpoints", mgrEndpoints), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-
-	var mgrEndpointFinal string
-	if len(mgrEndpoints) > 1 {
-		for _, member := range mgrEndpoints {
-			if !strings.Contains(member, "CIMC") {
-				mgrEndpointFinal = member
-				break
-			}
-		}
-	} else if len(mgrEndpoints) > 0 {
-		mgrEndpointFinal = mgrEndpoints[0]
-	} else {
-		return nil, errors.New("no manager endpoint found")
+	d := &Discovery{
+		ctx:      ctx,
+		exp:      &exp,
+		target:   target,
+		uri:      uri,
+		profile:  profile,
+		client:   retryClient,
+		excludes: excludes,
+		strict:   len(components) == 0,
 	}
 
-	log.Debug("mgr endpoint final decision", zap.String("mgr_endpoint_final", mgrEndpointFinal), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-
 	// prepend the base url with the chassis url
-	var chasUrlsFinal []string
 	for _, chasUrl := range chassisEndpoints {
-		chasUrlsFinal = append(chasUrlsFinal, exp.url+chasUrl)
+		d.chassis = append(d.chassis, exp.url+chasUrl)
 	}
 
-	log.Debug("chassis urls final", zap.Strings("chassis_urls_final", chasUrlsFinal), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-
-	// chassis endpoint to use for obtaining url endpoints for storage controller, NVMe drive metrics as well as the starting
-	// point for the systems and manager endpoints
-	sysEndpoints, err := getSystemEndpoints(chasUrlsFinal, target, profile, retryClient, excludes)
+	// the service root advertises the vendor of the BMC and the query parameters it supports
+	root, err := getServiceRoot(exp.url+uri, target, profile, retryClient)
 	if err != nil {
-		log.Error("error when getting chassis endpoints", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-		return nil, err
+		log.Debug("error when getting service root", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+	} else {
+		// collapse the collection walks with $expand and $select when the service root advertises them
+		exp.query = newQueryOptions(root.ProtocolFeaturesSupported)
 	}
 
-	// newer servers have volumes endpoint in storage controller, these volumes hold virtual drives member urls
-	if len(sysEndpoints.storageController) > 0 {
-		var controllerOutput oem.System
-		for _, controller := range sysEndpoints.storageController {
-			controllerOutput, err = getSystemsMetadata(exp.url+controller, target, profile, retryClient)
-			if err != nil {
-				log.Error("error when getting storage controller metadata", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-				return nil, err
-			}
-			if len(controllerOutput.Volumes.LinksURLSlice) > 0 {
-				for _, volume := range controllerOutput.Volumes.LinksURLSlice {
-					url := appendSlash(volume)
-					if reg, ok := excludes["drive"]; ok {
-						if !reg.(*regexp.Regexp).MatchString(url) {
-							if checkUnique(sysEndpoints.volumes, url) {
-								sysEndpoints.volumes = append(sysEndpoints.volumes, url)
-							}
-						}
-					}
-				}
-			}
+	exp.vendor = detectVendor(root, func() string {
+		// the manager model identifies the vendor of the older firmwares
+		if managers, err := d.managerMembers(); err == nil && len(managers) > 0 {
+			return exp.url + managers[0]
 		}
-	}
+		return ""
+	}, target, profile, retryClient)
 
-	if len(sysEndpoints.volumes) > 0 {
-		for _, volume := range sysEndpoints.volumes {
-			virtualDrives, err := getMemberUrls(exp.url+volume, target, profile, retryClient)
-			if err != nil {
-				log.Error("error when getting virtual drive member urls", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-				return nil, err
-			}
-			if len(virtualDrives) > 0 {
-				for _, virtualDrive := range virtualDrives {
-					if strings.Contains(virtualDrive, "Virtual") {
-						url := appendSlash(virtualDrive)
-						if checkUnique(sysEndpoints.virtualDrives, url) {
-							sysEndpoints.virtualDrives = append(sysEndpoints.virtualDrives, url)
-						}
-					}
-				}
-			}
-		}
-	}
+	log.Debug("vendor detected", zap.String("vendor", exp.vendor.Name), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 
-	log.Debug("systems endpoints response", zap.Strings("systems_endpoints", sysEndpoints.systems),
-		zap.Strings("storage_ctrl_endpoints", sysEndpoints.storageController),
-		zap.Strings("volumes_endpoints", sysEndpoints.volumes),
-		zap.Strings("virtual_drives_endpoints", sysEndpoints.virtualDrives),
-		zap.Strings("drives_endpoints", sysEndpoints.drives),
-		zap.Strings("power_endpoints", sysEndpoints.power),
-		zap.Strings("thermal_endpoints", sysEndpoints.thermal),
-		zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-
-	// check /redfish/v1/Systems/XXXXX/ exists so we don't panic
-	var sysResp oem.System
-	var dimms, processors oem.Collection
-	if len(sysEndpoints.systems) > 0 {
-		// call /redfish/v1/Systems/XXXXX/ for BIOS, Serial number
-		sysResp, err = getSystemsMetadata(exp.url+sysEndpoints.systems[0], target, profile, retryClient)
+	if d.strict {
+		mgrEndpoints, err := d.managerMembers()
 		if err != nil {
-			log.Error("error when getting BIOS version", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+			log.Error("error when getting manager endpoint", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 			return nil, err
 		}
-		exp.biosVersion = sysResp.BiosVersion
-		exp.ChassisSerialNumber = strings.TrimRight(sysResp.SerialNumber, " ")
-		exp.systemHostname = sysResp.SystemHostname
-
-		// call /redfish/v1/Systems/XXXXX/ for memory summary and smart storage batteries
-		// TODO: do not assume 1 systems endpoint
-		tasks = append(tasks,
-			pool.NewTask(common.Fetch(exp.url+sysEndpoints.systems[0], target, profile, retryClient),
-				exp.url+sysEndpoints.systems[0],
-				handle(&exp, MEMORY_SUMMARY, STORAGEBATTERY)))
-
-		// DIMM endpoints array
-		var systemMemoryEndpoint = GetMemoryURL(sysResp)
-		if systemMemoryEndpoint != "" {
-			dimms, err = getDIMMEndpoints(exp.url+systemMemoryEndpoint, target, profile, retryClient)
-			if err != nil {
-				log.Error("error when getting DIMM endpoints", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-				return nil, err
-			}
+		if len(mgrEndpoints) == 0 {
+			return nil, errors.New("no manager endpoint found")
 		}
 
-		// CPU processor metrics
-		processors, err = getProcessorEndpoints(exp.url+sysEndpoints.systems[0]+"Processors/", target, profile, retryClient)
+		sysEndpoints, _, err := d.systems()
 		if err != nil {
-			log.Error("error when getting Processors endpoints", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 			return nil, err
 		}
-	} else {
-		return nil, errors.New("no systems endpoint found")
+		if len(sysEndpoints.systems) == 0 {
+			return nil, errors.New("no systems endpoint found")
+		}
 	}
 
-	// check for SmartStorage endpoint from either Hp or Hpe
-	// skip if SmartStorage URL is not present
-	var ss = GetSmartStorageURL(sysResp)
-	var driveEndpointsResp DriveEndpoints
-	if ss != "" {
-		driveEndpointsResp, err = getAllDriveEndpoints(ctx, exp.url, exp.url+ss, target, profile, retryClient, excludes)
-		if err != nil {
-			log.Error("error when getting drive endpoints", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-			return nil, err
-		}
+	componentMap := make(map[ComponentType]bool, len(components))
+	for _, c := range components {
+		componentMap[c] = true
 	}
 
-	if (len(sysEndpoints.storageController) == 0 && ss == "") || (len(sysEndpoints.drives) == 0 && len(driveEndpointsResp.physicalDriveURLs) == 0) {
-		if sysResp.Storage.URL != "" {
-			url := appendSlash(sysResp.Storage.URL)
-			driveEndpointsResp, err = getAllDriveEndpoints(ctx, exp.url, exp.url+url, target, profile, retryClient, excludes)
-			if err != nil {
-				log.Error("error when getting drive endpoints", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-				return nil, err
+	for _, c := range collectors {
+		if d.strict {
+			if p, ok := c.collector.(PartialOnly); ok && p.PartialOnly() {
+				continue
 			}
+		} else if !componentMap[c.component] {
+			continue
 		}
-	}
 
-	log.Debug("drive endpoints response", zap.Strings("array_controller_endpoints", driveEndpointsResp.arrayControllerURLs),
-		zap.Strings("logical_drive_endpoints", driveEndpointsResp.logicalDriveURLs),
-		zap.Strings("physical_drive_endpoints", driveEndpointsResp.physicalDriveURLs),
-		zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-
-	//Firmware Inventory - Try the iLo 4 firmware inventory endpoints using sysEndpoints.systems URL
-	// call /redfish/v1/Systems/XXXX/FirmwareInventory/
-	var systemFML = GetFirmwareInventoryURL(sysResp)
-	var firmwareInventoryEndpoints []string
-	if systemFML != "" {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+systemFML, target, profile, retryClient), exp.url+systemFML, handle(&exp, FIRMWAREINVENTORY)))
-	} else {
-		// Check for /redfish/v1/Managers/XXXX/UpdateService/ for firmware inventory URL
-		rootComponents, err := getSystemsMetadata(exp.url+uri, target, profile, retryClient)
+		resources, err := c.collector.Discover(d)
 		if err != nil {
-			log.Error("error when getting root components metadata", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 			return nil, err
 		}
-		if rootComponents.UpdateService.URL != "" {
-			updateServiceEndpoints, err := getSystemsMetadata(exp.url+rootComponents.UpdateService.URL, target, profile, retryClient)
-			if err != nil {
-				log.Error("error when getting update service metadata", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-				return nil, err
-			}
-
-			if len(updateServiceEndpoints.FirmwareInventory.LinksURLSlice) == 1 {
-				firmwareInventoryEndpoints, err = getMemberUrls(exp.url+updateServiceEndpoints.FirmwareInventory.LinksURLSlice[0], target, profile, retryClient)
-				if err != nil {
-					log.Error("error when getting firmware inventory endpoints", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-					return nil, err
-				}
-			} else if len(updateServiceEndpoints.FirmwareInventory.LinksURLSlice) > 1 {
-				firmwareInventoryEndpoints = updateServiceEndpoints.FirmwareInventory.LinksURLSlice
-			}
-
-			if len(firmwareInventoryEndpoints) < 75 {
-				for _, fwEp := range firmwareInventoryEndpoints {
-					// this list can potentially be large and cause scrapes to take a long time
-					// see the '--collector.firmware.modules-exclude' config in the README for more information
-					if reg, ok := excludes["firmware"]; ok {
-						if !reg.(*regexp.Regexp).MatchString(fwEp) {
-							tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+fwEp, target, profile, retryClient), exp.url+fwEp, handle(&exp, FIRMWAREINVENTORY)))
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// Loop through arrayControllerURLs, logicalDriveURLs, physicalDriveURLs, and nvmeDriveURLs and append each URL to the tasks pool
-	for _, url := range driveEndpointsResp.arrayControllerURLs {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, STORAGE_CONTROLLER)))
-	}
-
-	for _, url := range driveEndpointsResp.logicalDriveURLs {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, LOGICALDRIVE)))
-	}
-
-	for _, url := range driveEndpointsResp.physicalDriveURLs {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, UNKNOWN_DRIVE)))
-	}
-
-	// drives from this list could either be NVMe or physical SAS/SATA
-	for _, url := range sysEndpoints.drives {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, UNKNOWN_DRIVE)))
-	}
-
-	// storage controller
-	for _, url := range sysEndpoints.storageController {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, STORAGE_CONTROLLER)))
+		tasks = append(tasks, exp.tasks(d, c, resources)...)
 	}
 
-	// virtual drives
-	for _, url := range sysEndpoints.virtualDrives {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, LOGICALDRIVE)))
-	}
-
-	// power
-	for _, url := range sysEndpoints.power {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, POWER)))
-	}
-
-	// thermal
-	for _, url := range sysEndpoints.thermal {
-		tasks = append(tasks, pool.NewTask(common.Fetch(exp.url+url, target, profile, retryClient), exp.url+url, handle(&exp, THERMAL)))
-	}
+	exp.pool = pool.NewPool(tasks, scrapeOptionsFromContext(ctx).concurrency())
 
-	// DIMMs
-	for _, dimm := range dimms.Members {
-		tasks = append(tasks,
-			pool.NewTask(common.Fetch(exp.url+dimm.URL, target, profile, retryClient), exp.url+dimm.URL, handle(&exp, MEMORY)))
+	// the plugins of partial scrapes see the system labels of a full scrape, the systems are only discovered by
+	// some components. The errors are logged by systems
+	if len(plugins) > 0 && !d.strict {
+		d.systems()
 	}
 
-	// call /redfish/v1/Managers/XXX/ for firmware version and ilo self test metrics
-	tasks = append(tasks,
-		pool.NewTask(common.Fetch(exp.url+mgrEndpointFinal, target, profile, retryClient),
-			exp.url+mgrEndpointFinal,
-			handle(&exp, FIRMWARE, ILOSELFTEST)))
-
-	for _, processor := range processors.Members {
-		tasks = append(tasks,
-			pool.NewTask(common.Fetch(exp.url+processor.URL, target, profile, retryClient), exp.url+processor.URL, handle(&exp, PROCESSOR)))
-	}
-
-	exp.pool = pool.NewPool(tasks, 1)
-
 	// check for any plugins, this feature allows one to collect any remaining component data not present inside
 	// the redfish API.
 	// Please see docs/plugins.md for more information.
@@ -464,6 +348,13 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 			n.Describe(ch)
 		}
 	}
+	if e.DeviceCounters != nil {
+		for _, m := range *e.DeviceCounters {
+			for _, n := range *m {
+				n.Describe(ch)
+			}
+		}
+	}
 }
 
 // Collect fetches the stats from configured fishymetrics location and delivers them
@@ -491,6 +382,13 @@ func (e *Exporter) resetMetrics() {
 			n.Reset()
 		}
 	}
+	if e.DeviceCounters != nil {
+		for _, m := range *e.DeviceCounters {
+			for _, n := range *m {
+				n.Reset()
+			}
+		}
+	}
 }
 
 func (e *Exporter) collectMetrics(metrics chan<- prometheus.Metric) {
@@ -499,6 +397,13 @@ func (e *Exporter) collectMetrics(metrics chan<- prometheus.Metric) {
 			n.Collect(metrics)
 		}
 	}
+	if e.DeviceCounters != nil {
+		for _, m := range *e.DeviceCounters {
+			for _, n := range *m {
+				n.Collect(metrics)
+			}
+		}
+	}
 }
 
 func (e *Exporter) scrape() {
@@ -507,7 +412,6 @@ func (e *Exporter) scrape() {
 	// Concurrently call the endpoints to help prevent reaching the maximum number of 4 simultaneous sessions
 	e.pool.Run()
 	for _, task := range e.pool.Tasks {
-		var err error
 		if task.Err != nil {
 			deviceState := uint8(0)
 			// If credentials are incorrect we will add host to be ignored until manual intervention
@@ -529,17 +433,18 @@ func (e *Exporter) scrape() {
 			return
 		}
 
+		// the kinds of a resource are parsed by separate handlers, each failure is logged
 		for _, handler := range task.MetricHandlers {
-			err = handler(task.Body)
-		}
-
-		if err != nil {
-			state = 0
-			log.Error("error exporting metrics", zap.Error(err), zap.String("url", task.URL), zap.Any("trace_id", e.ctx.Value(logging.TraceIDKey("traceID"))))
-			continue
+			if err := handler(task.Body); err != nil {
+				state = 0
+				log.Error("error exporting metrics", zap.Error(err), zap.String("url", task.URL), zap.Any("trace_id", e.ctx.Value(logging.TraceIDKey("traceID"))))
+			}
 		}
 	}
 
+	e.exportRedundancyMetrics()
+	e.exportRefreshMetrics()
+
 	var upMetric = (*e.DeviceMetrics)["up"]
 	(*upMetric)["up"].WithLabelValues().Set(float64(state))
 }
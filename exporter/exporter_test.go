@@ -19,11 +19,20 @@ package exporter
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/config"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -155,6 +164,188 @@ const (
         # TYPE redfish_power_supply_total_consumed gauge
         redfish_power_supply_total_consumed{chassisModel="model a",chassisSerialNumber="SN98765",memberId="/redfish/v1/Chassis/1/Power"} 206
 	`
+	GoodPowerControlCapacityExpected = `
+        # HELP redfish_power_control_capacity_watts Total power capacity available for allocation in watts
+        # TYPE redfish_power_control_capacity_watts gauge
+        redfish_power_control_capacity_watts{chassisModel="model a",chassisSerialNumber="SN98765",memberId="0"} 1600
+	`
+	GoodPowerControlLimitExpected = `
+        # HELP redfish_power_control_limit_watts Configured power cap in watts
+        # TYPE redfish_power_control_limit_watts gauge
+        redfish_power_control_limit_watts{chassisModel="model a",chassisSerialNumber="SN98765",memberId="0"} 1200
+	`
+	GoodPowerControlLimitExceptionExpected = `
+        # HELP redfish_power_control_limit_exception Action taken when the power cap is exceeded
+        # TYPE redfish_power_control_limit_exception gauge
+        redfish_power_control_limit_exception{chassisModel="model a",chassisSerialNumber="SN98765",limitException="LogEventOnly",memberId="0"} 1
+	`
+	GoodPowerControlMinConsumedExpected = `
+        # HELP redfish_power_control_min_consumed_watts Lowest power consumption over the BMC measurement interval in watts
+        # TYPE redfish_power_control_min_consumed_watts gauge
+        redfish_power_control_min_consumed_watts{chassisModel="model a",chassisSerialNumber="SN98765",memberId="0"} 205
+	`
+	GoodPowerControlMaxConsumedExpected = `
+        # HELP redfish_power_control_max_consumed_watts Highest power consumption over the BMC measurement interval in watts
+        # TYPE redfish_power_control_max_consumed_watts gauge
+        redfish_power_control_max_consumed_watts{chassisModel="model a",chassisSerialNumber="SN98765",memberId="0"} 282
+	`
+	GoodPowerControlIntervalExpected = `
+        # HELP redfish_power_control_interval_minutes BMC measurement interval for the min/avg/max consumption metrics in minutes
+        # TYPE redfish_power_control_interval_minutes gauge
+        redfish_power_control_interval_minutes{chassisModel="model a",chassisSerialNumber="SN98765",memberId="0"} 20
+	`
+	GoodPowerSupplyCapacityExpected = `
+        # HELP redfish_power_supply_capacity_watts The maximum capacity of the Power Supply in watts
+        # TYPE redfish_power_supply_capacity_watts gauge
+        redfish_power_supply_capacity_watts{bayNumber="1",chassisModel="model a",chassisSerialNumber="SN98765",firmwareVersion="2.04",manufacturer="DELTA",model="psmodel",name="HpeServerPowerSupply",powerSupplyType="AC",serialNumber="123456789"} 800
+	`
+	GoodPowerSupplyEfficiencyExpected = `
+        # HELP redfish_power_supply_efficiency_percent The rated efficiency of the Power Supply in percent
+        # TYPE redfish_power_supply_efficiency_percent gauge
+        redfish_power_supply_efficiency_percent{bayNumber="1",chassisModel="model a",chassisSerialNumber="SN98765",firmwareVersion="2.04",manufacturer="DELTA",model="psmodel",name="HpeServerPowerSupply",powerSupplyType="AC",serialNumber="123456789"} 94
+	`
+	GoodPowerSubsystemCapacityExpected = `
+        # HELP redfish_power_subsystem_capacity_watts Total power capacity of the PowerSubsystem in watts
+        # TYPE redfish_power_subsystem_capacity_watts gauge
+        redfish_power_subsystem_capacity_watts{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Chassis/1/PowerSubsystem"} 2400
+	`
+	GoodPowerSubsystemAllocatedExpected = `
+        # HELP redfish_power_subsystem_allocated_watts Total power allocated to the PowerSubsystem in watts
+        # TYPE redfish_power_subsystem_allocated_watts gauge
+        redfish_power_subsystem_allocated_watts{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Chassis/1/PowerSubsystem"} 1100
+	`
+	GoodPowerSubsystemSupplyStatusExpected = `
+        # HELP redfish_power_supply_status Current power supply status 1 = OK, 0 = BAD
+        # TYPE redfish_power_supply_status gauge
+        redfish_power_supply_status{bayNumber="PSU 1",chassisModel="model a",chassisSerialNumber="SN98765",firmwareVersion="1.2.3",manufacturer="DELTA",model="psmodel",name="Power Supply 1",powerSupplyType="AC",serialNumber="PS000001"} 1
+	`
+	GoodPowerSubsystemSupplyEfficiencyExpected = `
+        # HELP redfish_power_supply_efficiency_percent The rated efficiency of the Power Supply in percent
+        # TYPE redfish_power_supply_efficiency_percent gauge
+        redfish_power_supply_efficiency_percent{bayNumber="PSU 1",chassisModel="model a",chassisSerialNumber="SN98765",firmwareVersion="1.2.3",manufacturer="DELTA",model="psmodel",name="Power Supply 1",powerSupplyType="AC",serialNumber="PS000001"} 96
+	`
+	GoodPowerRedundancyStatusExpected = `
+        # HELP redfish_redundancy_status Current redundancy group status 1 = OK, 0 = BAD, -1 = DISABLED
+        # TYPE redfish_redundancy_status gauge
+        redfish_redundancy_status{chassisModel="model a",chassisSerialNumber="SN98765",group="PowerSupply Redundancy Group 1",mode="N+m",url="/redfish/v1/Chassis/1/Power"} 0
+	`
+	GoodPowerRedundancyHealthyExpected = `
+        # HELP redfish_redundancy_healthy_members Current number of healthy members in the redundancy group
+        # TYPE redfish_redundancy_healthy_members gauge
+        redfish_redundancy_healthy_members{chassisModel="model a",chassisSerialNumber="SN98765",group="PowerSupply Redundancy Group 1",mode="N+m",url="/redfish/v1/Chassis/1/Power"} 1
+	`
+	GoodPowerRedundancyMinExpected = `
+        # HELP redfish_redundancy_min_members Minimum number of members needed for the redundancy group to be redundant
+        # TYPE redfish_redundancy_min_members gauge
+        redfish_redundancy_min_members{chassisModel="model a",chassisSerialNumber="SN98765",group="PowerSupply Redundancy Group 1",mode="N+m",url="/redfish/v1/Chassis/1/Power"} 2
+	`
+	GoodFanRedundancyStatusExpected = `
+        # HELP redfish_redundancy_status Current redundancy group status 1 = OK, 0 = BAD, -1 = DISABLED
+        # TYPE redfish_redundancy_status gauge
+        redfish_redundancy_status{chassisModel="model a",chassisSerialNumber="SN98765",group="FanRedundancy/0",mode="NPlusM",url="/redfish/v1/Chassis/1/ThermalSubsystem"} 1
+	`
+	GoodFanRedundancyHealthyExpected = `
+        # HELP redfish_redundancy_healthy_members Current number of healthy members in the redundancy group
+        # TYPE redfish_redundancy_healthy_members gauge
+        redfish_redundancy_healthy_members{chassisModel="model a",chassisSerialNumber="SN98765",group="FanRedundancy/0",mode="NPlusM",url="/redfish/v1/Chassis/1/ThermalSubsystem"} 2
+	`
+	GoodFanRedundancyMaxExpected = `
+        # HELP redfish_redundancy_max_members Maximum number of members supported in the redundancy group
+        # TYPE redfish_redundancy_max_members gauge
+        redfish_redundancy_max_members{chassisModel="model a",chassisSerialNumber="SN98765",group="FanRedundancy/0",mode="NPlusM",url="/redfish/v1/Chassis/1/ThermalSubsystem"} 2
+	`
+	GoodThermalSubsystemFanSpeedExpected = `
+        # HELP redfish_thermal_fan_speed Current fan speed in the unit of percentage, possible values are 0 - 100
+        # TYPE redfish_thermal_fan_speed gauge
+        redfish_thermal_fan_speed{chassisModel="model a",chassisSerialNumber="SN98765",name="Fan 1"} 42
+	`
+	GoodSystemPowerStateExpected = `
+        # HELP redfish_system_power_state Current host power state 1 = On, 0 = Off or transitioning
+        # TYPE redfish_system_power_state gauge
+        redfish_system_power_state{chassisModel="model a",chassisSerialNumber="SN98765",powerState="On"} 1
+	`
+	GoodSystemBootProgressExpected = `
+        # HELP redfish_system_boot_progress Last host boot progress state 1 = OSRunning, 0 = any other state
+        # TYPE redfish_system_boot_progress gauge
+        redfish_system_boot_progress{chassisModel="model a",chassisSerialNumber="SN98765",lastState="SetupEntered"} 0
+	`
+	GoodSystemPostStateExpected = `
+        # HELP redfish_system_post_state Current HPE POST state 1 = FinishedPost, 0 = any other state
+        # TYPE redfish_system_post_state gauge
+        redfish_system_post_state{chassisModel="model a",chassisSerialNumber="SN98765",postState="InPost"} 0
+	`
+	GoodSystemIndicatorExpected = `
+        # HELP redfish_system_indicator_active Current location indicator state 1 = Lit or Blinking, 0 = Off
+        # TYPE redfish_system_indicator_active gauge
+        redfish_system_indicator_active{chassisModel="model a",chassisSerialNumber="SN98765",indicatorLED="Off"} 0
+	`
+	GoodSystemHealthRollupExpected = `
+        # HELP redfish_system_health_rollup Current system health rollup 1 = OK, 0 = BAD
+        # TYPE redfish_system_health_rollup gauge
+        redfish_system_health_rollup{chassisModel="model a",chassisSerialNumber="SN98765",healthRollup="Warning"} 0
+	`
+	GoodManagerStatusExpected = `
+        # HELP redfish_manager_status Current manager status 1 = OK, 0 = BAD, -1 = DISABLED
+        # TYPE redfish_manager_status gauge
+        redfish_manager_status{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Managers/1"} 1
+	`
+	GoodManagerClockOffsetExpected = `
+        # HELP redfish_manager_clock_offset_seconds Difference between the manager DateTime and the exporter clock in seconds, positive when the manager is ahead
+        # TYPE redfish_manager_clock_offset_seconds gauge
+        redfish_manager_clock_offset_seconds{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Managers/1"} -90
+	`
+	GoodManagerUptimeExpected = `
+        # HELP redfish_manager_uptime_seconds Time since the manager was last reset in seconds
+        # TYPE redfish_manager_uptime_seconds gauge
+        redfish_manager_uptime_seconds{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Managers/1"} 86400
+	`
+	GoodManagerMemoryUsedExpected = `
+        # HELP redfish_manager_memory_used_bytes Used memory of the manager in bytes
+        # TYPE redfish_manager_memory_used_bytes gauge
+        redfish_manager_memory_used_bytes{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Managers/1/ManagerDiagnosticData"} 268435456.0
+	`
+	GoodManagerFreeStorageExpected = `
+        # HELP redfish_manager_free_storage_bytes Free storage space of the manager in bytes
+        # TYPE redfish_manager_free_storage_bytes gauge
+        redfish_manager_free_storage_bytes{chassisModel="model a",chassisSerialNumber="SN98765",url="/redfish/v1/Managers/1/ManagerDiagnosticData"} 1048576.0
+	`
+	GoodSecurityProtocolExpected = `
+        # HELP redfish_security_protocol_enabled Current manager network protocol state 1 = enabled, 0 = disabled
+        # TYPE redfish_security_protocol_enabled gauge
+        redfish_security_protocol_enabled{chassisModel="model a",chassisSerialNumber="SN98765",port="22",protocol="SSH"} 1
+        redfish_security_protocol_enabled{chassisModel="model a",chassisSerialNumber="SN98765",port="443",protocol="HTTPS"} 1
+        redfish_security_protocol_enabled{chassisModel="model a",chassisSerialNumber="SN98765",port="623",protocol="IPMI"} 0
+	`
+	GoodSecurityAccountsEnabledExpected = `
+        # HELP redfish_security_accounts_enabled Number of enabled BMC accounts
+        # TYPE redfish_security_accounts_enabled gauge
+        redfish_security_accounts_enabled{chassisModel="model a",chassisSerialNumber="SN98765"} 2
+	`
+	GoodSecurityDefaultAccountExpected = `
+        # HELP redfish_security_default_account_enabled Is a factory default BMC account enabled 1 = enabled, 0 = not enabled
+        # TYPE redfish_security_default_account_enabled gauge
+        redfish_security_default_account_enabled{chassisModel="model a",chassisSerialNumber="SN98765"} 1
+	`
+	GoodSecurityLockoutThresholdExpected = `
+        # HELP redfish_security_account_lockout_threshold Number of failed logins before an account is locked, 0 = lockout disabled
+        # TYPE redfish_security_account_lockout_threshold gauge
+        redfish_security_account_lockout_threshold{chassisModel="model a",chassisSerialNumber="SN98765"} 5
+	`
+	GoodSecuritySecureBootExpected = `
+        # HELP redfish_security_secure_boot_enabled Current UEFI Secure Boot state 1 = enabled, 0 = disabled
+        # TYPE redfish_security_secure_boot_enabled gauge
+        redfish_security_secure_boot_enabled{chassisModel="model a",chassisSerialNumber="SN98765",mode="UserMode"} 1
+	`
+	GoodCertificateNotAfterExpected = `
+        # HELP redfish_certificate_not_after_seconds Certificate expiry time as a unix timestamp, usage Served is the certificate presented during the TLS handshake
+        # TYPE redfish_certificate_not_after_seconds gauge
+        redfish_certificate_not_after_seconds{chassisModel="model a",chassisSerialNumber="SN98765",issuer="Example CA",subject="bmc.example.com",usage="Web"} 1.7985024e+09
+	`
+	GoodSecurityTrustedModuleExpected = `
+        # HELP redfish_security_trusted_module_enabled Current trusted module state 1 = enabled, 0 = disabled
+        # TYPE redfish_security_trusted_module_enabled gauge
+        redfish_security_trusted_module_enabled{chassisModel="model a",chassisSerialNumber="SN98765",firmwareVersion="7.2",interfaceType="TPM2_0"} 1
+	`
 )
 
 type TestErrorResponse struct {
@@ -1107,6 +1298,311 @@ func Test_Exporter_Metrics_Handling(t *testing.T) {
   			]
   		}`)
 
+	var GoodPowerControlResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/Power",
+  			"Id": "Power",
+  			"Name": "PowerMetrics",
+  			"PowerControl": [
+  			  {
+  			    "MemberId": "0",
+  			    "PowerCapacityWatts": 1600,
+  			    "PowerConsumedWatts": 206,
+  			    "PowerLimit": {
+  			      "LimitInWatts": 1200,
+  			      "LimitException": "LogEventOnly"
+  			    },
+  			    "PowerMetrics": {
+  			      "AverageConsumedWatts": 207,
+  			      "IntervalInMin": 20,
+  			      "MaxConsumedWatts": 282,
+  			      "MinConsumedWatts": 205
+  			    }
+  			  }
+  			],
+  			"PowerSupplies": [
+  			  {
+  			    "EfficiencyPercent": 94,
+  			    "FirmwareVersion": "2.04",
+  			    "LastPowerOutputWatts": 91,
+  			    "Manufacturer": "DELTA",
+  			    "MemberId": "0",
+  			    "Model": "psmodel",
+  			    "Name": "HpeServerPowerSupply",
+  			    "Oem": {
+  			      "Hpe": {
+  			        "BayNumber": 1,
+  			        "PowerSupplyStatus": {
+  			          "State": "Ok"
+  			        }
+  			      }
+  			    },
+  			    "PowerCapacityWatts": 800,
+  			    "PowerSupplyType": "AC",
+  			    "SerialNumber": "123456789",
+  			    "Status": {
+  			      "Health": "OK",
+  			      "State": "Enabled"
+  			    }
+  			  }
+  			]
+  		}`)
+	var GoodPowerSubsystemResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/PowerSubsystem",
+  			"Id": "PowerSubsystem",
+  			"Name": "Power Subsystem",
+  			"CapacityWatts": 2400,
+  			"Allocation": {
+  			  "AllocatedWatts": 1100,
+  			  "RequestedWatts": 1150
+  			},
+  			"PowerSupplies": {
+  			  "@odata.id": "/redfish/v1/Chassis/1/PowerSubsystem/PowerSupplies"
+  			},
+  			"Status": {
+  			  "Health": "OK",
+  			  "State": "Enabled"
+  			}
+  		}`)
+	var GoodPowerSubsystemSupplyResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/PowerSubsystem/PowerSupplies/0",
+  			"Id": "0",
+  			"Name": "Power Supply 1",
+  			"EfficiencyRatings": [
+  			  {
+  			    "LoadPercent": 50,
+  			    "EfficiencyPercent": 96
+  			  }
+  			],
+  			"FirmwareVersion": "1.2.3",
+  			"Location": {
+  			  "PartLocation": {
+  			    "ServiceLabel": "PSU 1"
+  			  }
+  			},
+  			"Manufacturer": "DELTA",
+  			"Model": "psmodel",
+  			"PowerCapacityWatts": 1200,
+  			"PowerSupplyType": "AC",
+  			"SerialNumber": "PS000001",
+  			"Status": {
+  			  "Health": "OK",
+  			  "State": "Enabled"
+  			}
+  		}`)
+
+	var GoodPowerRedundancyResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/Power",
+  			"Id": "Power",
+  			"Name": "Power",
+  			"PowerSupplies": [
+  			  {
+  			    "@odata.id": "/redfish/v1/Chassis/1/Power#/PowerSupplies/0",
+  			    "MemberId": "0",
+  			    "Name": "PSU 1",
+  			    "Status": {
+  			      "Health": "OK",
+  			      "State": "Enabled"
+  			    }
+  			  },
+  			  {
+  			    "MemberId": "1",
+  			    "Name": "PSU 2",
+  			    "Status": {
+  			      "Health": "Critical",
+  			      "State": "Enabled"
+  			    }
+  			  }
+  			],
+  			"Redundancy": [
+  			  {
+  			    "@odata.id": "/redfish/v1/Chassis/1/Power#/Redundancy/0",
+  			    "MemberId": "0",
+  			    "Name": "PowerSupply Redundancy Group 1",
+  			    "Mode": "N+m",
+  			    "MaxNumSupported": 2,
+  			    "MinNumNeeded": 2,
+  			    "RedundancySet": [
+  			      {
+  			        "@odata.id": "/redfish/v1/Chassis/1/Power#/PowerSupplies/0"
+  			      },
+  			      {
+  			        "@odata.id": "/redfish/v1/Chassis/1/Power/#PowerSupplies/1"
+  			      }
+  			    ],
+  			    "Status": {
+  			      "Health": "Critical",
+  			      "State": "Enabled"
+  			    }
+  			  }
+  			]
+  		}`)
+	var GoodThermalSubsystemResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/ThermalSubsystem",
+  			"Id": "ThermalSubsystem",
+  			"Name": "Thermal Subsystem",
+  			"FanRedundancy": [
+  			  {
+  			    "MaxSupportedInGroup": 2,
+  			    "MinNeededInGroup": 1,
+  			    "RedundancyGroup": [
+  			      {
+  			        "@odata.id": "/redfish/v1/Chassis/1/ThermalSubsystem/Fans/1"
+  			      },
+  			      {
+  			        "@odata.id": "/redfish/v1/Chassis/1/ThermalSubsystem/Fans/2"
+  			      }
+  			    ],
+  			    "RedundancyType": "NPlusM",
+  			    "Status": {
+  			      "Health": "OK",
+  			      "State": "Enabled"
+  			    }
+  			  }
+  			],
+  			"Fans": {
+  			  "@odata.id": "/redfish/v1/Chassis/1/ThermalSubsystem/Fans"
+  			}
+  		}`)
+	var GoodThermalSubsystemFanResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/ThermalSubsystem/Fans/1",
+  			"Id": "1",
+  			"Name": "Fan 1",
+  			"SpeedPercent": {
+  			  "Reading": 42,
+  			  "SpeedRPM": 6720
+  			},
+  			"Status": {
+  			  "Health": "OK",
+  			  "State": "Enabled"
+  			}
+  		}`)
+	var GoodThermalSubsystemFan2Response = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/ThermalSubsystem/Fans/2",
+  			"Id": "2",
+  			"Name": "Fan 2",
+  			"SpeedPercent": {
+  			  "Reading": 40
+  			},
+  			"Status": {
+  			  "Health": "OK",
+  			  "State": "Enabled"
+  			}
+  		}`)
+
+	var GoodSystemResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Systems/1",
+  			"Id": "1",
+  			"BiosVersion": "U99 v0.00 (xx/xx/xxxx)",
+  			"BootProgress": {
+  			  "LastState": "SetupEntered"
+  			},
+  			"IndicatorLED": "Off",
+  			"Oem": {
+  			  "Hpe": {
+  			    "PostState": "InPost"
+  			  }
+  			},
+  			"PowerState": "On",
+  			"SerialNumber": "SN98765",
+  			"Status": {
+  			  "Health": "OK",
+  			  "HealthRollup": "Warning",
+  			  "State": "Enabled"
+  			}
+  		}`)
+
+	var GoodManagerResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Managers/1",
+  			"Id": "1",
+  			"DateTime": "2026-01-02T00:00:00+00:00",
+  			"FirmwareVersion": "2.10",
+  			"LastResetTime": "2026-01-01T00:00:00Z",
+  			"ManagerDiagnosticData": {
+  			  "@odata.id": "/redfish/v1/Managers/1/ManagerDiagnosticData"
+  			},
+  			"Status": {
+  			  "Health": "OK",
+  			  "State": "Enabled"
+  			}
+  		}`)
+	var GoodManagerDiagnosticResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Managers/1/ManagerDiagnosticData",
+  			"FreeStorageSpaceKiB": 1024,
+  			"MemoryStatistics": {
+  			  "AvailableBytes": 536870912,
+  			  "TotalBytes": 1073741824,
+  			  "UsedBytes": 268435456
+  			},
+  			"ProcessorStatistics": {
+  			  "KernelPercent": 12.5,
+  			  "UserPercent": 30.1
+  			}
+  		}`)
+
+	var GoodNetworkProtocolResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Managers/1/NetworkProtocol",
+  			"HTTPS": {
+  			  "Port": 443,
+  			  "ProtocolEnabled": true
+  			},
+  			"IPMI": {
+  			  "Port": 623,
+  			  "ProtocolEnabled": false
+  			},
+  			"SSH": {
+  			  "Port": 22,
+  			  "ProtocolEnabled": true
+  			}
+  		}`)
+	var GoodAccountServiceResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/AccountService",
+  			"AccountLockoutDuration": 300,
+  			"AccountLockoutThreshold": 5,
+  			"Accounts": {
+  			  "@odata.id": "/redfish/v1/AccountService/Accounts"
+  			},
+  			"MinPasswordLength": 8
+  		}`)
+	var GoodAccountResponses = [][]byte{
+		[]byte(`{"@odata.id": "/redfish/v1/AccountService/Accounts/1", "Enabled": false, "UserName": ""}`),
+		[]byte(`{"@odata.id": "/redfish/v1/AccountService/Accounts/2", "Enabled": true, "UserName": "root", "RoleId": "Administrator"}`),
+		[]byte(`{"@odata.id": "/redfish/v1/AccountService/Accounts/3", "Enabled": true, "UserName": "monitor", "RoleId": "ReadOnly"}`),
+		[]byte(`{"@odata.id": "/redfish/v1/AccountService/Accounts/4", "Enabled": false, "UserName": "olduser", "RoleId": "Operator"}`),
+	}
+	var GoodSecureBootResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Systems/1/SecureBoot",
+  			"SecureBootCurrentBoot": "Enabled",
+  			"SecureBootEnable": true,
+  			"SecureBootMode": "UserMode"
+  		}`)
+	var GoodTrustedModulesResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Systems/1",
+  			"TrustedModules": [
+  			  {
+  			    "FirmwareVersion": "7.2",
+  			    "InterfaceType": "TPM2_0",
+  			    "Status": {
+  			      "State": "Enabled"
+  			    }
+  			  }
+  			]
+  		}`)
+
+	var GoodCertificateResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Managers/1/NetworkProtocol/HTTPS/Certificates/1",
+  			"CertificateType": "PEM",
+  			"Issuer": {
+  			  "CommonName": "Example CA",
+  			  "Organization": "Example"
+  			},
+  			"Subject": {
+  			  "CommonName": "bmc.example.com",
+  			  "Organization": "Example"
+  			},
+  			"ValidNotAfter": "2026-12-29T00:00:00Z",
+  			"ValidNotBefore": "2025-12-29T00:00:00Z"
+  		}`)
+
 	var exporter prometheus.Collector
 
 	assert := assert.New(t)
@@ -1219,63 +1715,183 @@ func Test_Exporter_Metrics_Handling(t *testing.T) {
 		return nil
 	}
 
-	tests := []struct {
-		name       string
-		metricName string
-		metricRef1 string
-		metricRef2 string
-		handleFunc func(*Exporter, []byte) error
-		response   []byte
-		expected   string
-	}{
-		{
-			name:       "Good Device Info",
-			metricName: "redfish_device_info",
-			metricRef1: "deviceInfo",
-			metricRef2: "deviceInfo",
-			handleFunc: deviceInfoMetrics,
-			response:   GoodDeviceInfoResponse,
-			expected:   GoodDeviceInfoExpected,
-		},
-		{
-			name:       "Good CPU Status",
-			metricName: "redfish_cpu_status",
-			metricRef1: "processorMetrics",
-			metricRef2: "processorStatus",
-			handleFunc: processorMetrics,
-			response:   GoodCPUStatusResponse,
-			expected:   GoodCPUStatusExpected,
-		},
-		{
-			name:       "Good Logical Drive",
-			metricName: "redfish_logical_drive_status",
-			metricRef1: "logicalDriveMetrics",
-			metricRef2: "raidStatus",
-			handleFunc: logicalDevMetrics,
-			response:   GoodLogicalDriveResponse,
-			expected:   GoodLogicalDriveExpected,
-		},
-		{
-			name:       "Good Disk Drive",
-			metricName: "redfish_disk_drive_status",
-			metricRef1: "diskDriveMetrics",
-			metricRef2: "driveStatus",
-			handleFunc: physDevMetrics,
-			response:   GoodDiskDriveResponse,
-			expected:   GoodDiskDriveExpected,
-		},
-		{
-			name:       "Good Nvme Drive",
-			metricName: "redfish_nvme_drive_status",
-			metricRef1: "nvmeMetrics",
-			metricRef2: "nvmeDriveStatus",
-			handleFunc: nvmeDevMetrics,
-			response:   GoodNvmeDriveResponse,
-			expected:   GoodNvmeDriveExpected,
-		},
-		{
-			name:       "Good Storage Controller",
-			metricName: "redfish_storage_controller_status",
+	powSubsystemMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportPowerSubsystemMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	powSupplyMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportPowerSupplyMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	powRedundancyMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportPowerMetrics(payload)
+		if err != nil {
+			return err
+		}
+		exp.exportRedundancyMetrics()
+		return nil
+	}
+
+	fanRedundancyMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportThermalSubsystemMetrics(payload)
+		if err != nil {
+			return err
+		}
+		for _, fan := range [][]byte{GoodThermalSubsystemFanResponse, GoodThermalSubsystemFan2Response} {
+			err = exp.exportFanMetrics(fan)
+			if err != nil {
+				return err
+			}
+		}
+		exp.exportRedundancyMetrics()
+		return nil
+	}
+
+	fanMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportFanMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	systemMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportSystemMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	managerMetrics := func(exp *Exporter, payload []byte) error {
+		// the manager response was received 90 seconds after its DateTime
+		exp.managerFetchedAt = time.Date(2026, 1, 2, 0, 1, 30, 0, time.UTC)
+		err := exp.exportManagerMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	managerDiagMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportManagerDiagnosticMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	networkProtocolMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportNetworkProtocolMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	accountMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportAccountServiceMetrics(payload)
+		if err != nil {
+			return err
+		}
+		for _, account := range GoodAccountResponses {
+			err = exp.exportAccountMetrics(account)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	secureBootMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportSecureBootMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	trustedModulesMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportTrustedModulesMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	httpsCertificateMetrics := func(exp *Exporter, payload []byte) error {
+		err := exp.exportHTTPSCertificateMetrics(payload)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		metricName string
+		metricRef1 string
+		metricRef2 string
+		handleFunc func(*Exporter, []byte) error
+		response   []byte
+		expected   string
+	}{
+		{
+			name:       "Good Device Info",
+			metricName: "redfish_device_info",
+			metricRef1: "deviceInfo",
+			metricRef2: "deviceInfo",
+			handleFunc: deviceInfoMetrics,
+			response:   GoodDeviceInfoResponse,
+			expected:   GoodDeviceInfoExpected,
+		},
+		{
+			name:       "Good CPU Status",
+			metricName: "redfish_cpu_status",
+			metricRef1: "processorMetrics",
+			metricRef2: "processorStatus",
+			handleFunc: processorMetrics,
+			response:   GoodCPUStatusResponse,
+			expected:   GoodCPUStatusExpected,
+		},
+		{
+			name:       "Good Logical Drive",
+			metricName: "redfish_logical_drive_status",
+			metricRef1: "logicalDriveMetrics",
+			metricRef2: "raidStatus",
+			handleFunc: logicalDevMetrics,
+			response:   GoodLogicalDriveResponse,
+			expected:   GoodLogicalDriveExpected,
+		},
+		{
+			name:       "Good Disk Drive",
+			metricName: "redfish_disk_drive_status",
+			metricRef1: "diskDriveMetrics",
+			metricRef2: "driveStatus",
+			handleFunc: physDevMetrics,
+			response:   GoodDiskDriveResponse,
+			expected:   GoodDiskDriveExpected,
+		},
+		{
+			name:       "Good Nvme Drive",
+			metricName: "redfish_nvme_drive_status",
+			metricRef1: "nvmeMetrics",
+			metricRef2: "nvmeDriveStatus",
+			handleFunc: nvmeDevMetrics,
+			response:   GoodNvmeDriveResponse,
+			expected:   GoodNvmeDriveExpected,
+		},
+		{
+			name:       "Good Storage Controller",
+			metricName: "redfish_storage_controller_status",
 			metricRef1: "storageCtrlMetrics",
 			metricRef2: "storageControllerStatus",
 			handleFunc: storCtrlMetrics,
@@ -1444,6 +2060,330 @@ func Test_Exporter_Metrics_Handling(t *testing.T) {
 			response:   GoodPowerSupplyTotalConsumedResponse,
 			expected:   GoodPowerSupplyTotalConsumedExpected,
 		},
+		{
+			name:       "Good Power Control Capacity",
+			metricName: "redfish_power_control_capacity_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "controlCapacity",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerControlCapacityExpected,
+		},
+		{
+			name:       "Good Power Control Limit",
+			metricName: "redfish_power_control_limit_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "controlLimit",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerControlLimitExpected,
+		},
+		{
+			name:       "Good Power Control Limit Exception",
+			metricName: "redfish_power_control_limit_exception",
+			metricRef1: "powerMetrics",
+			metricRef2: "controlLimitException",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerControlLimitExceptionExpected,
+		},
+		{
+			name:       "Good Power Control Min Consumed",
+			metricName: "redfish_power_control_min_consumed_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "controlMinConsumed",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerControlMinConsumedExpected,
+		},
+		{
+			name:       "Good Power Control Max Consumed",
+			metricName: "redfish_power_control_max_consumed_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "controlMaxConsumed",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerControlMaxConsumedExpected,
+		},
+		{
+			name:       "Good Power Control Interval",
+			metricName: "redfish_power_control_interval_minutes",
+			metricRef1: "powerMetrics",
+			metricRef2: "controlInterval",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerControlIntervalExpected,
+		},
+		{
+			name:       "Good Power Supply Capacity",
+			metricName: "redfish_power_supply_capacity_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "supplyCapacity",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerSupplyCapacityExpected,
+		},
+		{
+			name:       "Good Power Supply Efficiency",
+			metricName: "redfish_power_supply_efficiency_percent",
+			metricRef1: "powerMetrics",
+			metricRef2: "supplyEfficiency",
+			handleFunc: powMetrics,
+			response:   GoodPowerControlResponse,
+			expected:   GoodPowerSupplyEfficiencyExpected,
+		},
+		{
+			name:       "Good Power Subsystem Capacity",
+			metricName: "redfish_power_subsystem_capacity_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "subsystemCapacity",
+			handleFunc: powSubsystemMetrics,
+			response:   GoodPowerSubsystemResponse,
+			expected:   GoodPowerSubsystemCapacityExpected,
+		},
+		{
+			name:       "Good Power Subsystem Allocated",
+			metricName: "redfish_power_subsystem_allocated_watts",
+			metricRef1: "powerMetrics",
+			metricRef2: "subsystemAllocated",
+			handleFunc: powSubsystemMetrics,
+			response:   GoodPowerSubsystemResponse,
+			expected:   GoodPowerSubsystemAllocatedExpected,
+		},
+		{
+			name:       "Good Power Subsystem Supply Status",
+			metricName: "redfish_power_supply_status",
+			metricRef1: "powerMetrics",
+			metricRef2: "supplyStatus",
+			handleFunc: powSupplyMetrics,
+			response:   GoodPowerSubsystemSupplyResponse,
+			expected:   GoodPowerSubsystemSupplyStatusExpected,
+		},
+		{
+			name:       "Good Power Subsystem Supply Efficiency",
+			metricName: "redfish_power_supply_efficiency_percent",
+			metricRef1: "powerMetrics",
+			metricRef2: "supplyEfficiency",
+			handleFunc: powSupplyMetrics,
+			response:   GoodPowerSubsystemSupplyResponse,
+			expected:   GoodPowerSubsystemSupplyEfficiencyExpected,
+		},
+		{
+			name:       "Good Power Redundancy Status",
+			metricName: "redfish_redundancy_status",
+			metricRef1: "redundancyMetrics",
+			metricRef2: "redundancyStatus",
+			handleFunc: powRedundancyMetrics,
+			response:   GoodPowerRedundancyResponse,
+			expected:   GoodPowerRedundancyStatusExpected,
+		},
+		{
+			name:       "Good Power Redundancy Healthy Members",
+			metricName: "redfish_redundancy_healthy_members",
+			metricRef1: "redundancyMetrics",
+			metricRef2: "redundancyHealthyMembers",
+			handleFunc: powRedundancyMetrics,
+			response:   GoodPowerRedundancyResponse,
+			expected:   GoodPowerRedundancyHealthyExpected,
+		},
+		{
+			name:       "Good Power Redundancy Min Members",
+			metricName: "redfish_redundancy_min_members",
+			metricRef1: "redundancyMetrics",
+			metricRef2: "redundancyMinMembers",
+			handleFunc: powRedundancyMetrics,
+			response:   GoodPowerRedundancyResponse,
+			expected:   GoodPowerRedundancyMinExpected,
+		},
+		{
+			name:       "Good Fan Redundancy Status",
+			metricName: "redfish_redundancy_status",
+			metricRef1: "redundancyMetrics",
+			metricRef2: "redundancyStatus",
+			handleFunc: fanRedundancyMetrics,
+			response:   GoodThermalSubsystemResponse,
+			expected:   GoodFanRedundancyStatusExpected,
+		},
+		{
+			name:       "Good Fan Redundancy Healthy Members",
+			metricName: "redfish_redundancy_healthy_members",
+			metricRef1: "redundancyMetrics",
+			metricRef2: "redundancyHealthyMembers",
+			handleFunc: fanRedundancyMetrics,
+			response:   GoodThermalSubsystemResponse,
+			expected:   GoodFanRedundancyHealthyExpected,
+		},
+		{
+			name:       "Good Fan Redundancy Max Members",
+			metricName: "redfish_redundancy_max_members",
+			metricRef1: "redundancyMetrics",
+			metricRef2: "redundancyMaxMembers",
+			handleFunc: fanRedundancyMetrics,
+			response:   GoodThermalSubsystemResponse,
+			expected:   GoodFanRedundancyMaxExpected,
+		},
+		{
+			name:       "Good Thermal Subsystem Fan Speed",
+			metricName: "redfish_thermal_fan_speed",
+			metricRef1: "thermalMetrics",
+			metricRef2: "fanSpeed",
+			handleFunc: fanMetrics,
+			response:   GoodThermalSubsystemFanResponse,
+			expected:   GoodThermalSubsystemFanSpeedExpected,
+		},
+		{
+			name:       "Good System Power State",
+			metricName: "redfish_system_power_state",
+			metricRef1: "systemMetrics",
+			metricRef2: "powerState",
+			handleFunc: systemMetrics,
+			response:   GoodSystemResponse,
+			expected:   GoodSystemPowerStateExpected,
+		},
+		{
+			name:       "Good System Boot Progress",
+			metricName: "redfish_system_boot_progress",
+			metricRef1: "systemMetrics",
+			metricRef2: "bootProgress",
+			handleFunc: systemMetrics,
+			response:   GoodSystemResponse,
+			expected:   GoodSystemBootProgressExpected,
+		},
+		{
+			name:       "Good System HPE Post State",
+			metricName: "redfish_system_post_state",
+			metricRef1: "systemMetrics",
+			metricRef2: "postState",
+			handleFunc: systemMetrics,
+			response:   GoodSystemResponse,
+			expected:   GoodSystemPostStateExpected,
+		},
+		{
+			name:       "Good System Indicator LED",
+			metricName: "redfish_system_indicator_active",
+			metricRef1: "systemMetrics",
+			metricRef2: "indicatorActive",
+			handleFunc: systemMetrics,
+			response:   GoodSystemResponse,
+			expected:   GoodSystemIndicatorExpected,
+		},
+		{
+			name:       "Good System Health Rollup",
+			metricName: "redfish_system_health_rollup",
+			metricRef1: "systemMetrics",
+			metricRef2: "healthRollup",
+			handleFunc: systemMetrics,
+			response:   GoodSystemResponse,
+			expected:   GoodSystemHealthRollupExpected,
+		},
+		{
+			name:       "Good Manager Status",
+			metricName: "redfish_manager_status",
+			metricRef1: "managerMetrics",
+			metricRef2: "managerStatus",
+			handleFunc: managerMetrics,
+			response:   GoodManagerResponse,
+			expected:   GoodManagerStatusExpected,
+		},
+		{
+			name:       "Good Manager Clock Offset",
+			metricName: "redfish_manager_clock_offset_seconds",
+			metricRef1: "managerMetrics",
+			metricRef2: "managerClockOffset",
+			handleFunc: managerMetrics,
+			response:   GoodManagerResponse,
+			expected:   GoodManagerClockOffsetExpected,
+		},
+		{
+			name:       "Good Manager Uptime",
+			metricName: "redfish_manager_uptime_seconds",
+			metricRef1: "managerMetrics",
+			metricRef2: "managerUptime",
+			handleFunc: managerMetrics,
+			response:   GoodManagerResponse,
+			expected:   GoodManagerUptimeExpected,
+		},
+		{
+			name:       "Good Manager Diagnostic Memory Used",
+			metricName: "redfish_manager_memory_used_bytes",
+			metricRef1: "managerMetrics",
+			metricRef2: "managerMemoryUsed",
+			handleFunc: managerDiagMetrics,
+			response:   GoodManagerDiagnosticResponse,
+			expected:   GoodManagerMemoryUsedExpected,
+		},
+		{
+			name:       "Good Manager Diagnostic Free Storage",
+			metricName: "redfish_manager_free_storage_bytes",
+			metricRef1: "managerMetrics",
+			metricRef2: "managerFreeStorage",
+			handleFunc: managerDiagMetrics,
+			response:   GoodManagerDiagnosticResponse,
+			expected:   GoodManagerFreeStorageExpected,
+		},
+		{
+			name:       "Good Security Network Protocols",
+			metricName: "redfish_security_protocol_enabled",
+			metricRef1: "securityMetrics",
+			metricRef2: "protocolEnabled",
+			handleFunc: networkProtocolMetrics,
+			response:   GoodNetworkProtocolResponse,
+			expected:   GoodSecurityProtocolExpected,
+		},
+		{
+			name:       "Good Security Accounts Enabled",
+			metricName: "redfish_security_accounts_enabled",
+			metricRef1: "securityMetrics",
+			metricRef2: "accountsEnabled",
+			handleFunc: accountMetrics,
+			response:   GoodAccountServiceResponse,
+			expected:   GoodSecurityAccountsEnabledExpected,
+		},
+		{
+			name:       "Good Security Default Account Enabled",
+			metricName: "redfish_security_default_account_enabled",
+			metricRef1: "securityMetrics",
+			metricRef2: "defaultAccountEnabled",
+			handleFunc: accountMetrics,
+			response:   GoodAccountServiceResponse,
+			expected:   GoodSecurityDefaultAccountExpected,
+		},
+		{
+			name:       "Good Security Account Lockout Threshold",
+			metricName: "redfish_security_account_lockout_threshold",
+			metricRef1: "securityMetrics",
+			metricRef2: "accountLockoutThreshold",
+			handleFunc: accountMetrics,
+			response:   GoodAccountServiceResponse,
+			expected:   GoodSecurityLockoutThresholdExpected,
+		},
+		{
+			name:       "Good Security Secure Boot",
+			metricName: "redfish_security_secure_boot_enabled",
+			metricRef1: "securityMetrics",
+			metricRef2: "secureBootEnabled",
+			handleFunc: secureBootMetrics,
+			response:   GoodSecureBootResponse,
+			expected:   GoodSecuritySecureBootExpected,
+		},
+		{
+			name:       "Good Security Trusted Module",
+			metricName: "redfish_security_trusted_module_enabled",
+			metricRef1: "securityMetrics",
+			metricRef2: "trustedModuleEnabled",
+			handleFunc: trustedModulesMetrics,
+			response:   GoodTrustedModulesResponse,
+			expected:   GoodSecurityTrustedModuleExpected,
+		},
+		{
+			name:       "Good HTTPS Certificate Not After",
+			metricName: "redfish_certificate_not_after_seconds",
+			metricRef1: "certificateMetrics",
+			metricRef2: "certificateNotAfter",
+			handleFunc: httpsCertificateMetrics,
+			response:   GoodCertificateResponse,
+			expected:   GoodCertificateNotAfterExpected,
+		},
 	}
 
 	for _, test := range tests {
@@ -1463,3 +2403,1302 @@ func Test_Exporter_Metrics_Handling(t *testing.T) {
 	}
 	prometheus.Unregister(exporter)
 }
+
+func Test_Exporter_Power_Control_Members(t *testing.T) {
+	exp := &Exporter{
+		ctx:                 context.Background(),
+		host:                "fishymetrics.com",
+		Model:               "model a",
+		ChassisSerialNumber: "SN98765",
+		DeviceMetrics:       NewDeviceMetrics(),
+	}
+
+	err := exp.exportPowerMetrics([]byte(`{
+		"@odata.id": "/redfish/v1/Chassis/1/Power",
+		"PowerControl": [
+			{"MemberId": "0", "PowerCapacityWatts": 1600},
+			{"MemberId": "1", "PowerCapacityWatts": 800}
+		]
+	}`))
+	assert.Nil(t, err)
+
+	// each PowerControl entry of the chassis keeps its own series
+	m := (*(*exp.DeviceMetrics)["powerMetrics"])["controlCapacity"]
+	assert.Equal(t, 1600.0, testutil.ToFloat64(m.WithLabelValues("0", "SN98765", "model a")))
+	assert.Equal(t, 800.0, testutil.ToFloat64(m.WithLabelValues("1", "SN98765", "model a")))
+}
+
+func Test_Exporter_Manager_Time_Formats(t *testing.T) {
+	exp := &Exporter{
+		ctx:                 context.Background(),
+		host:                "fishymetrics.com",
+		Model:               "model a",
+		ChassisSerialNumber: "SN98765",
+		DeviceMetrics:       NewDeviceMetrics(),
+	}
+
+	// a timestamp that isn't RFC3339 only skips the clock metrics
+	err := exp.exportManagerMetrics([]byte(`{
+		"@odata.id": "/redfish/v1/Managers/1",
+		"Status": {"State": "Enabled", "Health": "OK"},
+		"DateTime": "Fri Jan 2 00:00:00 2026",
+		"LastResetTime": "2026-01-01T00:00:00Z"
+	}`))
+	assert.Nil(t, err)
+
+	m := *(*exp.DeviceMetrics)["managerMetrics"]
+	assert.Equal(t, 1.0, testutil.ToFloat64(m["managerStatus"].WithLabelValues("/redfish/v1/Managers/1", "SN98765", "model a")))
+	assert.Equal(t, 0, testutil.CollectAndCount(m["managerClockOffset"]))
+	assert.Equal(t, 1, testutil.CollectAndCount(m["managerLastReset"]))
+}
+
+func Test_fetchWithTime(t *testing.T) {
+	var fetchedAt time.Time
+	start := time.Now()
+	_, err := fetchWithTime(func() ([]byte, error) {
+		time.Sleep(40 * time.Millisecond)
+		return nil, nil
+	}, &fetchedAt)()
+	end := time.Now()
+	assert.Nil(t, err)
+
+	// the midpoint of the request, not when the body was read
+	assert.True(t, fetchedAt.After(start.Add(10*time.Millisecond)) && fetchedAt.Before(end.Add(-10*time.Millisecond)), "fetchedAt %s not in the middle of %s and %s", fetchedAt, start, end)
+}
+
+func Test_Exporter_Energy_Counters(t *testing.T) {
+	var GoodEnvironmentMetricsResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/EnvironmentMetrics",
+  			"Id": "EnvironmentMetrics",
+  			"Name": "Chassis Environment Metrics",
+  			"EnergykWh": {
+  			  "DataSourceUri": "/redfish/v1/Chassis/1/Sensors/EnergykWh",
+  			  "Reading": 1.5,
+  			  "SensorResetTime": "2026-01-01T00:00:00Z"
+  			}
+  		}`)
+	var ResetEnvironmentMetricsResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/EnvironmentMetrics",
+  			"Id": "EnvironmentMetrics",
+  			"Name": "Chassis Environment Metrics",
+  			"EnergykWh": {
+  			  "DataSourceUri": "/redfish/v1/Chassis/1/Sensors/EnergykWh",
+  			  "Reading": 0.5,
+  			  "SensorResetTime": "2026-02-01T00:00:00Z"
+  			}
+  		}`)
+	var GoodPowerSupplyMetricsResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Chassis/1/PowerSubsystem/PowerSupplies/0/Metrics",
+  			"Id": "Metrics",
+  			"Name": "Metrics for Power Supply 1",
+  			"EnergykWh": {
+  			  "Reading": 2
+  			}
+  		}`)
+
+	const (
+		GoodEnergyExpected = `
+        # HELP redfish_energy_joules_total Total energy consumed in joules, accumulated across BMC sensor resets
+        # TYPE redfish_energy_joules_total counter
+        redfish_energy_joules_total{chassisModel="model a",chassisSerialNumber="SN98765",sensor="/redfish/v1/Chassis/1/Sensors/EnergykWh",url="/redfish/v1/Chassis/1/EnvironmentMetrics"} 5.4e+06
+	`
+		ResetEnergyExpected = `
+        # HELP redfish_energy_joules_total Total energy consumed in joules, accumulated across BMC sensor resets
+        # TYPE redfish_energy_joules_total counter
+        redfish_energy_joules_total{chassisModel="model a",chassisSerialNumber="SN98765",sensor="/redfish/v1/Chassis/1/Sensors/EnergykWh",url="/redfish/v1/Chassis/1/EnvironmentMetrics"} 7.2e+06
+	`
+		GoodPowerSupplyEnergyExpected = `
+        # HELP redfish_energy_joules_total Total energy consumed in joules, accumulated across BMC sensor resets
+        # TYPE redfish_energy_joules_total counter
+        redfish_energy_joules_total{chassisModel="model a",chassisSerialNumber="SN98765",sensor="EnergykWh",url="/redfish/v1/Chassis/1/PowerSubsystem/PowerSupplies/0/Metrics"} 7.2e+06
+	`
+	)
+
+	assert := assert.New(t)
+
+	// each test case uses a fresh exporter like a scrape would, the energy totals persist between them
+	tests := []struct {
+		name      string
+		responses [][]byte
+		expected  string
+	}{
+		{
+			name:      "Good Environment Metrics Energy",
+			responses: [][]byte{GoodEnvironmentMetricsResponse},
+			expected:  GoodEnergyExpected,
+		},
+		{
+			name:      "Environment Metrics Energy Sensor Reset",
+			responses: [][]byte{GoodEnvironmentMetricsResponse, ResetEnvironmentMetricsResponse},
+			expected:  ResetEnergyExpected,
+		},
+		{
+			name:      "Good Power Supply Metrics Energy",
+			responses: [][]byte{GoodPowerSupplyMetricsResponse},
+			expected:  GoodPowerSupplyEnergyExpected,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			energyCounters = energyStore{
+				counters: make(map[energyKey]*energyCounter),
+			}
+
+			var exp *Exporter
+			for _, response := range test.responses {
+				exp = &Exporter{
+					ctx:                 context.Background(),
+					host:                "fishymetrics.com",
+					Model:               "model a",
+					ChassisSerialNumber: "SN98765",
+					DeviceMetrics:       NewDeviceMetrics(),
+					DeviceCounters:      NewDeviceCounters(),
+				}
+
+				err := exp.exportEnergyMetrics(response)
+				if err != nil {
+					t.Error(err)
+				}
+			}
+
+			m := (*(*exp.DeviceCounters)["energyMetrics"])["energyTotal"]
+			assert.Empty(testutil.CollectAndCompare(m, strings.NewReader(test.expected), "redfish_energy_joules_total"))
+		})
+	}
+}
+
+func Test_energyStore_Prune(t *testing.T) {
+	s := energyStore{
+		counters: make(map[energyKey]*energyCounter),
+	}
+	idle := energyKey{host: "a", url: "/redfish/v1/Chassis/1/EnvironmentMetrics", sensor: "EnergyJoules"}
+	active := energyKey{host: "a", url: "/redfish/v1/Chassis/1/EnvironmentMetrics", sensor: "EnergykWh"}
+
+	s.observe(idle, 10, "")
+	s.observe(active, 10, "")
+	s.counters[idle].lastSeen = time.Now().Add(-2 * energyCounterTTL)
+	s.lastPrune = time.Time{}
+
+	assert.Equal(t, 15.0, s.observe(active, 15, ""))
+	assert.NotContains(t, s.counters, idle)
+	// the sensor starts over from its reading when it's scraped again
+	assert.Equal(t, 12.0, s.observe(idle, 12, ""))
+}
+
+func Test_Exporter_Log_Entries(t *testing.T) {
+	var GoodLogEntriesResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/",
+  			"Members@odata.count": 2,
+  			"Members": [
+  			  {
+  			    "@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/1/",
+  			    "Id": "1",
+  			    "Created": "2026-03-01T10:00:00Z",
+  			    "EntryType": "Oem",
+  			    "Message": "POST Error: 1796-Memory Initialization Error",
+  			    "Severity": "Critical"
+  			  },
+  			  {
+  			    "@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/2/",
+  			    "Id": "2",
+  			    "Created": "2026-03-01T10:05:00Z",
+  			    "EntryType": "Oem",
+  			    "Message": "Server power restored.",
+  			    "Severity": "OK"
+  			  }
+  			]
+  		}`)
+	var NewLogEntriesResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/",
+  			"Members@odata.count": 4,
+  			"Members": [
+  			  {
+  			    "@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/1/",
+  			    "Id": "1",
+  			    "Created": "2026-03-01T10:00:00Z",
+  			    "Message": "POST Error: 1796-Memory Initialization Error",
+  			    "Severity": "Critical"
+  			  },
+  			  {
+  			    "@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/2/",
+  			    "Id": "2",
+  			    "Created": "2026-03-01T10:05:00Z",
+  			    "Message": "Server power restored.",
+  			    "Severity": "OK"
+  			  },
+  			  {
+  			    "@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/3/",
+  			    "Id": "3",
+  			    "Created": "2026-03-01T10:05:00Z",
+  			    "Message": "Power Supply Failure (Power Supply 2)",
+  			    "MessageSeverity": "Warning"
+  			  },
+  			  {
+  			    "@odata.id": "/redfish/v1/Systems/1/LogServices/IML/Entries/4/",
+  			    "Id": "4",
+  			    "Created": "2026-03-01T11:00:00Z",
+  			    "Message": "Uncorrectable Memory Error",
+  			    "MessageSeverity": "Critical"
+  			  }
+  			]
+  		}`)
+
+	const (
+		GoodLogEntriesExpected = `
+        # HELP redfish_log_entries_total Total number of log service entries by severity, counted from when the exporter first read the service
+        # TYPE redfish_log_entries_total counter
+        redfish_log_entries_total{chassisModel="model a",chassisSerialNumber="SN98765",service="IML",severity="Critical"} 1
+        redfish_log_entries_total{chassisModel="model a",chassisSerialNumber="SN98765",service="IML",severity="OK"} 1
+	`
+		NewLogEntriesExpected = `
+        # HELP redfish_log_entries_total Total number of log service entries by severity, counted from when the exporter first read the service
+        # TYPE redfish_log_entries_total counter
+        redfish_log_entries_total{chassisModel="model a",chassisSerialNumber="SN98765",service="IML",severity="Critical"} 2
+        redfish_log_entries_total{chassisModel="model a",chassisSerialNumber="SN98765",service="IML",severity="OK"} 1
+        redfish_log_entries_total{chassisModel="model a",chassisSerialNumber="SN98765",service="IML",severity="Warning"} 1
+	`
+	)
+
+	assert := assert.New(t)
+
+	// each test case uses a fresh exporter like a scrape would, the high-water mark persists between them
+	tests := []struct {
+		name      string
+		responses [][]byte
+		expected  string
+	}{
+		{
+			name:      "Good Log Entries Baseline",
+			responses: [][]byte{GoodLogEntriesResponse},
+			expected:  GoodLogEntriesExpected,
+		},
+		{
+			name:      "Log Entries Unchanged",
+			responses: [][]byte{GoodLogEntriesResponse, GoodLogEntriesResponse},
+			expected:  GoodLogEntriesExpected,
+		},
+		{
+			name:      "New Log Entries Past High-Water Mark",
+			responses: [][]byte{GoodLogEntriesResponse, NewLogEntriesResponse, NewLogEntriesResponse},
+			expected:  NewLogEntriesExpected,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logEntries = logStore{
+				services: make(map[string]*logState),
+			}
+
+			var exp *Exporter
+			for _, response := range test.responses {
+				exp = &Exporter{
+					ctx:                 context.Background(),
+					host:                "fishymetrics.com",
+					Model:               "model a",
+					ChassisSerialNumber: "SN98765",
+					DeviceMetrics:       NewDeviceMetrics(),
+					DeviceCounters:      NewDeviceCounters(),
+				}
+
+				err := exp.exportLogEntryMetrics(response)
+				if err != nil {
+					t.Error(err)
+				}
+			}
+
+			m := (*(*exp.DeviceCounters)["logMetrics"])["logEntriesTotal"]
+			assert.Empty(testutil.CollectAndCompare(m, strings.NewReader(test.expected), "redfish_log_entries_total"))
+		})
+	}
+}
+
+func Test_logStore_Observe(t *testing.T) {
+	assert := assert.New(t)
+
+	store := logStore{
+		services: make(map[string]*logState),
+	}
+
+	first := []oem.LogEntry{
+		{ID: "1", Created: "2026-03-01T10:00:00Z", Severity: "OK"},
+		{ID: "2", Created: "2026-03-01T10:05:00Z", Severity: "OK"},
+	}
+	next := append(first,
+		oem.LogEntry{ID: "3", Created: "2026-03-01T10:05:00Z", MessageSeverity: "Warning"},
+		oem.LogEntry{ID: "4", Created: "2026-03-01T11:00:00Z"},
+	)
+
+	newEntries, counts := store.observe("host/Entries/", first)
+	assert.Empty(newEntries)
+	assert.Equal(map[string]float64{"OK": 2}, counts)
+
+	newEntries, counts = store.observe("host/Entries/", next)
+	assert.Len(newEntries, 2)
+	assert.Equal("3", newEntries[0].ID)
+	assert.Equal("4", newEntries[1].ID)
+	assert.Equal(map[string]float64{"OK": 2, "Warning": 1, "Unknown": 1}, counts)
+
+	mark, ok := store.highWaterMark("host/Entries/")
+	assert.True(ok)
+	assert.Equal("2026-03-01T11:00:00Z", mark.Format(time.RFC3339))
+
+	newEntries, _ = store.observe("host/Entries/", next)
+	assert.Empty(newEntries)
+}
+
+func Test_logStore_Observe_Undated(t *testing.T) {
+	assert := assert.New(t)
+
+	store := logStore{
+		services: make(map[string]*logState),
+	}
+
+	first := []oem.LogEntry{
+		{ID: "1", Created: "2026-03-01T10:00:00Z", Severity: "OK"},
+		{ID: "2", Created: "01/03/2026 10:05", Severity: "OK"},
+	}
+	next := append(first, oem.LogEntry{ID: "3", Severity: "Critical"})
+
+	newEntries, counts := store.observe("host/Entries/", first)
+	assert.Empty(newEntries)
+	assert.Equal(map[string]float64{"OK": 2}, counts)
+
+	// the entries without a valid Created time are counted once by id
+	newEntries, counts = store.observe("host/Entries/", next)
+	assert.Len(newEntries, 1)
+	assert.Equal("3", newEntries[0].ID)
+	assert.Equal(map[string]float64{"OK": 2, "Critical": 1}, counts)
+
+	newEntries, counts = store.observe("host/Entries/", next)
+	assert.Empty(newEntries)
+	assert.Equal(map[string]float64{"OK": 2, "Critical": 1}, counts)
+}
+
+func Test_logStore_Prune(t *testing.T) {
+	store := logStore{
+		services: make(map[string]*logState),
+	}
+	entries := []oem.LogEntry{{ID: "1", Created: "2026-03-01T10:00:00Z", Severity: "OK"}}
+
+	store.observe("a/Entries/", entries)
+	store.observe("b/Entries/", entries)
+	store.services["a/Entries/"].lastSeen = time.Now().Add(-2 * logStateTTL)
+	store.lastPrune = time.Time{}
+
+	store.observe("b/Entries/", entries)
+	assert.NotContains(t, store.services, "a/Entries/")
+	assert.Contains(t, store.services, "b/Entries/")
+}
+
+func Test_fetchLogEntries_Skip(t *testing.T) {
+	const total, pageSize = 100, 2
+
+	// an oldest first collection of total entries, two per page
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		start, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+		coll := oem.LogEntryCollection{MembersCount: total}
+		for i := start; i < start+pageSize && i < total; i++ {
+			coll.Members = append(coll.Members, oem.LogEntry{
+				ID:      strconv.Itoa(i + 1),
+				Created: time.Date(2026, 3, 1, 0, i, 0, 0, time.UTC).Format(time.RFC3339),
+			})
+		}
+		if start+pageSize < total {
+			coll.NextLink = r.URL.Path + "?$skip=" + strconv.Itoa(start+pageSize)
+		}
+		_ = json.NewEncoder(w).Encode(coll)
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 0
+
+	tests := []struct {
+		name  string
+		skip  bool
+		first string
+		last  string
+	}{
+		{
+			name:  "Oldest First Without Skip",
+			skip:  false,
+			first: "1",
+			last:  "40",
+		},
+		{
+			name:  "Oldest First With Skip",
+			skip:  true,
+			first: "63",
+			last:  "100",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests = nil
+			url := "/redfish/v1/Systems/1/LogServices/SEL/Entries/"
+			body, err := fetchLogEntries(server.URL, url, logServiceKey("fishymetrics.com", url), "", "", client, test.skip)()
+			assert.Nil(t, err)
+
+			var coll oem.LogEntryCollection
+			assert.Nil(t, json.Unmarshal(body, &coll))
+			assert.Len(t, requests, logMaxPages)
+			assert.Equal(t, total, coll.MembersCount)
+			assert.Equal(t, url, coll.Url)
+			assert.Equal(t, test.first, coll.Members[0].ID)
+			assert.Equal(t, test.last, coll.Members[len(coll.Members)-1].ID)
+		})
+	}
+}
+
+func Test_Exporter_Telemetry_Metrics(t *testing.T) {
+	var GoodMetricReportResponse = []byte(`{
+  			"@odata.id": "/redfish/v1/TelemetryService/MetricReports/ThermalSensor",
+  			"Id": "ThermalSensor",
+  			"Name": "Thermal Sensor Metric Report",
+  			"MetricValues": [
+  			  {
+  			    "MetricId": "TemperatureReading",
+  			    "MetricProperty": "/redfish/v1/Chassis/System.Embedded.1/Sensors/CPU1Temp",
+  			    "MetricValue": "45",
+  			    "Timestamp": "2026-03-01T10:00:00Z"
+  			  },
+  			  {
+  			    "MetricId": "TemperatureReading",
+  			    "MetricProperty": "/redfish/v1/Chassis/System.Embedded.1/Sensors/CPU1Temp",
+  			    "MetricValue": "47",
+  			    "Timestamp": "2026-03-01T10:01:00Z"
+  			  },
+  			  {
+  			    "MetricId": "TemperatureReading",
+  			    "MetricProperty": "/redfish/v1/Chassis/System.Embedded.1/Sensors/InletTemp",
+  			    "MetricValue": 22.5
+  			  },
+  			  {
+  			    "MetricId": "RPMReading",
+  			    "MetricProperty": "/redfish/v1/Chassis/System.Embedded.1/Sensors/Fan1A",
+  			    "MetricValue": "7200"
+  			  },
+  			  {
+  			    "MetricId": "SystemHealth",
+  			    "MetricValue": "Warning"
+  			  }
+  			]
+  		}`)
+
+	const GoodMetricReportExpected = `
+        # HELP redfish_telemetry_rpm_reading Redfish telemetry metric report value of MetricId RPMReading
+        # TYPE redfish_telemetry_rpm_reading gauge
+        redfish_telemetry_rpm_reading{chassisModel="model a",chassisSerialNumber="SN98765",property="/redfish/v1/Chassis/System.Embedded.1/Sensors/Fan1A",report="ThermalSensor"} 7200
+        # HELP redfish_telemetry_temperature_reading Redfish telemetry metric report value of MetricId TemperatureReading
+        # TYPE redfish_telemetry_temperature_reading gauge
+        redfish_telemetry_temperature_reading{chassisModel="model a",chassisSerialNumber="SN98765",property="/redfish/v1/Chassis/System.Embedded.1/Sensors/CPU1Temp",report="ThermalSensor"} 47
+        redfish_telemetry_temperature_reading{chassisModel="model a",chassisSerialNumber="SN98765",property="/redfish/v1/Chassis/System.Embedded.1/Sensors/InletTemp",report="ThermalSensor"} 22.5
+	`
+
+	assert := assert.New(t)
+
+	exp := &Exporter{
+		ctx:                 context.Background(),
+		host:                "fishymetrics.com",
+		Model:               "model a",
+		ChassisSerialNumber: "SN98765",
+		DeviceMetrics:       NewDeviceMetrics(),
+		DeviceCounters:      NewDeviceCounters(),
+	}
+
+	err := exp.exportTelemetryMetrics(GoodMetricReportResponse)
+	if err != nil {
+		t.Error(err)
+	}
+
+	registry := prometheus.NewRegistry()
+	for _, m := range *(*exp.DeviceMetrics)["telemetryMetrics"] {
+		registry.MustRegister(m)
+	}
+	assert.Len(*(*exp.DeviceMetrics)["telemetryMetrics"], 2)
+	assert.Empty(testutil.GatherAndCompare(registry, strings.NewReader(GoodMetricReportExpected)))
+}
+
+func Test_telemetryMetricName(t *testing.T) {
+	tests := []struct {
+		metricID string
+		expected string
+	}{
+		{metricID: "TemperatureReading", expected: "redfish_telemetry_temperature_reading"},
+		{metricID: "CPUTemp", expected: "redfish_telemetry_cpu_temp"},
+		{metricID: "CPU1Temp", expected: "redfish_telemetry_cpu1_temp"},
+		{metricID: "SystemInputPower", expected: "redfish_telemetry_system_input_power"},
+		{metricID: "PSU.Input-Power", expected: "redfish_telemetry_psu_input_power"},
+		{metricID: "rpm", expected: "redfish_telemetry_rpm"},
+		{metricID: "--", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.metricID, func(t *testing.T) {
+			assert.Equal(t, test.expected, telemetryMetricName(test.metricID))
+		})
+	}
+}
+
+func Test_Exporter_Served_Certificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	served := server.Certificate()
+	servedPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: served.Raw})
+
+	const (
+		ServedMatchExpected = `
+        # HELP redfish_certificate_served_match Does the served TLS certificate match one of the manager HTTPS certificates 1 = match, 0 = mismatch
+        # TYPE redfish_certificate_served_match gauge
+        redfish_certificate_served_match{chassisModel="model a",chassisSerialNumber="SN98765"} 1
+	`
+		ServedMismatchExpected = `
+        # HELP redfish_certificate_served_match Does the served TLS certificate match one of the manager HTTPS certificates 1 = match, 0 = mismatch
+        # TYPE redfish_certificate_served_match gauge
+        redfish_certificate_served_match{chassisModel="model a",chassisSerialNumber="SN98765"} 0
+	`
+	)
+
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		response []byte
+		expected string
+	}{
+		{
+			name:     "Served Certificate Matches CertificateString",
+			response: MustMarshal(oem.Certificate{CertificateString: string(servedPEM), ValidNotAfter: served.NotAfter.Format(time.RFC3339)}),
+			expected: ServedMatchExpected,
+		},
+		{
+			name:     "Served Certificate Matches SerialNumber",
+			response: MustMarshal(oem.Certificate{SerialNumber: served.SerialNumber.Text(16)}),
+			expected: ServedMatchExpected,
+		},
+		{
+			name:     "Served Certificate Mismatch",
+			response: MustMarshal(oem.Certificate{SerialNumber: "01", ValidNotAfter: "2020-01-01T00:00:00Z"}),
+			expected: ServedMismatchExpected,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exp := &Exporter{
+				ctx:                 context.Background(),
+				host:                "fishymetrics.com",
+				Model:               "model a",
+				ChassisSerialNumber: "SN98765",
+				DeviceMetrics:       NewDeviceMetrics(),
+				tlsRecorder:         &tlsRecorder{leaf: served},
+			}
+
+			err := exp.exportServedCertificateMetrics(nil)
+			if err != nil {
+				t.Error(err)
+			}
+			err = exp.exportHTTPSCertificateMetrics(test.response)
+			if err != nil {
+				t.Error(err)
+			}
+
+			m := (*(*exp.DeviceMetrics)["certificateMetrics"])["certificateServedMatch"]
+			assert.Empty(testutil.CollectAndCompare(m, strings.NewReader(test.expected), "redfish_certificate_served_match"))
+		})
+	}
+}
+
+func Test_Exporter_Optional_Resource(t *testing.T) {
+	assert := assert.New(t)
+	exp := &Exporter{ctx: context.Background(), DeviceMetrics: NewDeviceMetrics()}
+	d := &Discovery{ctx: context.Background(), exp: exp}
+	c := registeredCollector{component: ComponentCertificates, collector: &builtinCollector{discover: discoverCertificates}}
+
+	failed := func() ([]byte, error) { return nil, errors.New("HTTP status 404") }
+	tasks := exp.tasks(d, c, []Resource{
+		{URL: "/redfish/v1/Certificates/1/", Kinds: []string{CERTIFICATE}, Fetch: failed, NoRefresh: true, Optional: true},
+		{URL: "/redfish/v1/Certificates/2/", Kinds: []string{CERTIFICATE}, Fetch: failed, NoRefresh: true},
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+	for _, task := range tasks {
+		task.Run(&wg)
+	}
+
+	// the failed optional certificate is skipped, the other one fails the scrape
+	assert.Nil(tasks[0].Err)
+	for _, handler := range tasks[0].MetricHandlers {
+		assert.Nil(handler(tasks[0].Body))
+	}
+	assert.NotNil(tasks[1].Err)
+}
+
+func Test_newQueryOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		features oem.ProtocolFeaturesSupported
+		expand   string
+		selected bool
+	}{
+		{
+			name:     "No Query Support",
+			features: oem.ProtocolFeaturesSupported{},
+		},
+		{
+			name:     "Expand NoLinks",
+			features: oem.ProtocolFeaturesSupported{ExpandQuery: oem.ExpandQuery{NoLinks: true}},
+			expand:   "$expand=.",
+		},
+		{
+			name:     "Expand NoLinks With Levels And Select",
+			features: oem.ProtocolFeaturesSupported{ExpandQuery: oem.ExpandQuery{NoLinks: true, Levels: true, MaxLevels: 6}, SelectQuery: true},
+			expand:   "$expand=.($levels=1)",
+			selected: true,
+		},
+		{
+			name:     "Expand Links Only",
+			features: oem.ProtocolFeaturesSupported{ExpandQuery: oem.ExpandQuery{Links: true, ExpandAll: true}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := newQueryOptions(test.features)
+			assert.Equal(t, test.expand, q.expand)
+			assert.Equal(t, test.selected, q.selectQuery)
+		})
+	}
+}
+
+func Test_jsonProperties(t *testing.T) {
+	type first struct {
+		ID       string `json:"@odata.id"`
+		Name     string `json:"Name"`
+		Status   string `json:"Status,omitempty"`
+		Internal string `json:"-"`
+		NoTag    string
+	}
+	type second struct {
+		Name     string `json:"Name"`
+		Capacity int    `json:"CapacityMiB"`
+	}
+
+	assert.Equal(t, []string{"Name", "Status", "CapacityMiB"}, jsonProperties(first{}, second{}))
+}
+
+func Test_queryOptions_Collection_Member(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		switch {
+		case r.URL.Path == "/redfish/v1/Systems/1/Memory/" && r.URL.Query().Get("$expand") != "":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/1/Memory/proc1dimm1","Name":"proc1dimm1"},{"@odata.id":"/redfish/v1/Systems/1/Memory/proc1dimm2"}]}`))
+		case r.URL.Path == "/redfish/v1/Systems/1/Processors/" && r.URL.Query().Get("$expand") != "":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.URL.Query().Get("$select") != "":
+			w.WriteHeader(http.StatusNotImplemented)
+		default:
+			_, _ = w.Write([]byte(`{"Name":"plain"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 0
+
+	assert := assert.New(t)
+	q := newQueryOptions(oem.ProtocolFeaturesSupported{ExpandQuery: oem.ExpandQuery{NoLinks: true}, SelectQuery: true})
+
+	// expanded members are kept, members with only their @odata.id are not
+	_, err := q.collection(server.URL+"/redfish/v1/Systems/1/Memory/", "", "", client)()
+	assert.Nil(err)
+	body, err := q.member(server.URL+"/redfish/v1/Systems/1/Memory/proc1dimm1/", "", "", client, MEMORY)()
+	assert.Nil(err)
+	assert.JSONEq(`{"@odata.id":"/redfish/v1/Systems/1/Memory/proc1dimm1","Name":"proc1dimm1"}`, string(body))
+	assert.Len(requests, 1)
+
+	// a rejected $select falls back to a plain request and is not requested again
+	body, err = q.member(server.URL+"/redfish/v1/Systems/1/Memory/proc1dimm2", "", "", client, MEMORY)()
+	assert.Nil(err)
+	assert.JSONEq(`{"Name":"plain"}`, string(body))
+	assert.False(q.selectQuery)
+	assert.Len(requests, 3)
+
+	// a rejected $expand falls back to a plain request and is not requested again
+	body, err = q.collection(server.URL+"/redfish/v1/Systems/1/Processors/", "", "", client)()
+	assert.Nil(err)
+	assert.JSONEq(`{"Name":"plain"}`, string(body))
+	assert.Equal("", q.expand)
+	assert.Len(requests, 5)
+
+	// a nil queryOptions fetches the uri as is
+	var none *queryOptions
+	_, err = none.collection(server.URL+"/redfish/v1/Systems/1/Storage/", "", "", client)()
+	assert.Nil(err)
+	assert.Equal("/redfish/v1/Systems/1/Storage/", requests[len(requests)-1])
+}
+
+func Test_ParseRefreshIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals string
+		expected  map[string]time.Duration
+		err       bool
+	}{
+		{
+			name:      "Empty",
+			intervals: "",
+			expected:  map[string]time.Duration{},
+		},
+		{
+			name:      "Sensor And Inventory Components",
+			intervals: "thermal=30s,firmware=6h, Memory=30m,drives=15m",
+			expected:  map[string]time.Duration{"thermal": 30 * time.Second, "firmware": 6 * time.Hour, "memory": 30 * time.Minute, "drives": 15 * time.Minute},
+		},
+		{
+			name:      "Invalid Component",
+			intervals: "fans=1m",
+			err:       true,
+		},
+		{
+			name:      "Invalid Duration",
+			intervals: "firmware=6",
+			err:       true,
+		},
+		{
+			name:      "Missing Duration",
+			intervals: "firmware",
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			intervals, err := ParseRefreshIntervals(test.intervals)
+			if test.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, intervals)
+		})
+	}
+}
+
+func Test_ParseCacheMaxAge(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxAge   string
+		expected map[string]time.Duration
+		err      bool
+	}{
+		{
+			name:     "Empty",
+			maxAge:   "",
+			expected: map[string]time.Duration{},
+		},
+		{
+			name:     "Inventory Components",
+			maxAge:   "firmware=6h, Memory=30m,drives=15m",
+			expected: map[string]time.Duration{"firmware": 6 * time.Hour, "memory": 30 * time.Minute, "drives": 15 * time.Minute},
+		},
+		{
+			name:   "Sensor Component",
+			maxAge: "thermal=1m",
+			err:    true,
+		},
+		{
+			name:   "Invalid Duration",
+			maxAge: "firmware=6",
+			err:    true,
+		},
+		{
+			name:   "Missing Duration",
+			maxAge: "firmware",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			maxAge, err := ParseCacheMaxAge(test.maxAge)
+			if test.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, maxAge)
+		})
+	}
+}
+
+func Test_refreshInterval_CacheMaxAge(t *testing.T) {
+	config.NewConfig(nil)
+	config.GetConfig().RefreshIntervals = map[string]time.Duration{"firmware": time.Hour}
+	config.GetConfig().CacheMaxAge = map[string]time.Duration{"firmware": 6 * time.Hour, "memory": 30 * time.Minute}
+	defer func() {
+		config.GetConfig().RefreshIntervals = nil
+		config.GetConfig().CacheMaxAge = nil
+	}()
+
+	// the refresh intervals take precedence over the deprecated cache max-age
+	assert.Equal(t, time.Hour, refreshInterval(ComponentFirmware))
+	assert.Equal(t, 30*time.Minute, refreshInterval(ComponentMemory))
+	assert.Equal(t, time.Duration(0), refreshInterval(ComponentThermal))
+}
+
+func Test_refreshStore_Prune(t *testing.T) {
+	s := refreshStore{
+		targets: make(map[string]*refreshTarget),
+	}
+
+	s.mark("idle", ComponentFirmware)
+	s.mark("active", ComponentFirmware)
+	s.targets["idle"].lastSeen = time.Now().Add(-2 * refreshTargetTTL)
+	s.targets["active"].lastSeen = time.Now().Add(-2 * refreshTargetTTL)
+	// the active target is still scraped, its firmware is served from the last result
+	_, ok := s.get("active", ComponentFirmware)
+	assert.True(t, ok)
+	s.lastPrune = time.Time{}
+
+	s.mark("active", ComponentThermal)
+	assert.NotContains(t, s.targets, "idle")
+	assert.Contains(t, s.targets, "active")
+}
+
+func Test_Exporter_Refresh_Intervals(t *testing.T) {
+	config.NewConfig(nil)
+	config.GetConfig().RefreshIntervals = map[string]time.Duration{"firmware": time.Hour}
+	defer func() { config.GetConfig().RefreshIntervals = nil }()
+
+	componentRefreshes = refreshStore{
+		targets: make(map[string]*refreshTarget),
+	}
+
+	assert := assert.New(t)
+
+	var calls = map[ComponentType]int{}
+	fetch := func(component ComponentType) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			calls[component]++
+			return []byte(`{}`), nil
+		}
+	}
+
+	// each scrape uses a fresh exporter, firmware is served from the first result while thermal is fetched every time
+	var exp *Exporter
+	for i := 0; i < 2; i++ {
+		exp = &Exporter{
+			ctx:                 context.Background(),
+			host:                "refresh.fishymetrics.com",
+			Model:               "model a",
+			ChassisSerialNumber: "SN98765",
+			DeviceMetrics:       NewDeviceMetrics(),
+		}
+
+		for _, component := range []ComponentType{ComponentFirmware, ComponentThermal} {
+			_, err := exp.withRefresh(component, "https://refresh.fishymetrics.com/redfish/v1/"+string(component), fetch(component))()
+			assert.Nil(err)
+		}
+		exp.exportRefreshMetrics()
+	}
+
+	assert.Equal(1, calls[ComponentFirmware])
+	assert.Equal(2, calls[ComponentThermal])
+
+	m := (*(*exp.DeviceMetrics)["refreshMetrics"])["componentLastRefresh"]
+	assert.Equal(2, testutil.CollectAndCount(m, "redfish_component_last_refresh_timestamp_seconds"))
+
+	firmware, _ := componentRefreshes.get("refresh.fishymetrics.com", ComponentFirmware)
+	assert.Equal(float64(firmware.Unix()), testutil.ToFloat64(m.WithLabelValues("firmware", "SN98765", "model a")))
+}
+
+func Test_detectVendor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/Managers/1/":
+			_, _ = w.Write([]byte(`{"Model":"iDRAC 8"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+	client.RetryMax = 0
+
+	tests := []struct {
+		name       string
+		root       oem.ServiceRoot
+		managerURL string
+		expected   string
+	}{
+		{
+			name:     "Oem Key",
+			root:     oem.ServiceRoot{Oem: map[string]json.RawMessage{"Hpe": json.RawMessage(`{}`)}},
+			expected: "hpe",
+		},
+		{
+			name:     "Vendor",
+			root:     oem.ServiceRoot{Vendor: "Cisco Systems Inc."},
+			expected: "cisco",
+		},
+		{
+			name:     "Product",
+			root:     oem.ServiceRoot{Product: "Lenovo XClarity Controller"},
+			expected: "lenovo",
+		},
+		{
+			name:       "Manager Model",
+			managerURL: server.URL + "/redfish/v1/Managers/1/",
+			expected:   "dell",
+		},
+		{
+			name:     "Unknown",
+			root:     oem.ServiceRoot{Vendor: "Acme"},
+			expected: "unknown",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target := "vendor-" + test.name
+			v := detectVendor(test.root, func() string { return test.managerURL }, target, "", client)
+			assert.Equal(t, test.expected, v.Name)
+			assert.Equal(t, v.Quirks, common.TargetQuirks.Get(target))
+		})
+	}
+
+	// the vendor of the previous scrape is kept when the service root can't be fetched
+	v := detectVendor(oem.ServiceRoot{}, func() string { return "" }, "vendor-Vendor", "", client)
+	assert.Equal(t, "cisco", v.Name)
+}
+
+func Test_Vendor_manager(t *testing.T) {
+	assert := assert.New(t)
+	v := &Vendor{SkipManagers: []string{"CIMC"}}
+
+	assert.Equal("", v.manager(nil))
+	assert.Equal("/redfish/v1/Managers/1/", v.manager([]string{"/redfish/v1/Managers/CIMC/", "/redfish/v1/Managers/1/"}))
+	assert.Equal("/redfish/v1/Managers/CIMC/", v.manager([]string{"/redfish/v1/Managers/CIMC/"}))
+}
+
+func Test_Collectors_PartialOnly(t *testing.T) {
+	var partialOnly []ComponentType
+	for _, c := range collectors {
+		if p, ok := c.collector.(PartialOnly); ok && p.PartialOnly() {
+			partialOnly = append(partialOnly, c.component)
+		}
+	}
+
+	// the certificates are collected by full scrapes
+	assert.Equal(t, []ComponentType{ComponentSecurity, ComponentLogs, ComponentTelemetry}, partialOnly)
+}
+
+type testCollector struct{}
+
+func (testCollector) Discover(d *Discovery) ([]Resource, error) {
+	return []Resource{{URL: d.URI() + "/Fake/", Kinds: []string{"fake"}}}, nil
+}
+
+func (testCollector) Describe() map[string]*prometheus.GaugeVec {
+	return map[string]*prometheus.GaugeVec{
+		"value": prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "redfish_fake_value",
+			Help: "value of the fake resource",
+		}, []string{"chassisModel"}),
+	}
+}
+
+func (testCollector) Parse(e *Exporter, kind string, body []byte) error {
+	var v struct {
+		Value float64
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return err
+	}
+	(*(*e.DeviceMetrics)["fake"])["value"].WithLabelValues(e.Model).Set(v.Value)
+	return nil
+}
+
+func Test_RegisterCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1":
+			w.Write([]byte(`{}`))
+		case "/redfish/v1/Chassis/":
+			w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Chassis/1/"}]}`))
+		case "/redfish/v1/Managers/":
+			w.Write([]byte(`{"Members":[]}`))
+		case "/redfish/v1/Fake/":
+			w.Write([]byte(`{"Value":3}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// the bad credentials tests add the test servers host to the ignored list
+	delete(common.IgnoredDevices, "127.0.0.1")
+
+	RegisterCollector("fake", testCollector{})
+	defer func() {
+		collectors = collectors[:len(collectors)-1]
+		delete(ValidComponents, "fake")
+	}()
+
+	assert := assert.New(t)
+	assert.Panics(func() { RegisterCollector("fake", testCollector{}) })
+	assert.Equal("fake", ComponentNames()[len(ComponentNames())-1])
+
+	components, err := ParseComponents("fake")
+	assert.Nil(err)
+
+	exp, err := NewPartialExporter(context.Background(), server.URL, "/redfish/v1", "", "model a", nil, components)
+	if !assert.Nil(err) {
+		return
+	}
+	assert.Len(exp.pool.Tasks, 1)
+
+	const expected = `
+        # HELP redfish_fake_value value of the fake resource
+        # TYPE redfish_fake_value gauge
+        redfish_fake_value{chassisModel="model a"} 3
+	`
+	assert.Empty(testutil.CollectAndCompare(exp, strings.NewReader(expected), "redfish_fake_value"))
+}
+
+func Test_jsonPath(t *testing.T) {
+	var doc interface{}
+	err := json.Unmarshal([]byte(`{"Status":{"Health":"OK"},"Temperatures":[{"Name":"CPU 1","ReadingCelsius":40},{"Name":"CPU 2","ReadingCelsius":45}],"Oem":{"Hpe":{"@odata.type":"#HpeServerChassis"}}}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected []interface{}
+		err      bool
+	}{
+		{
+			name:     "Child",
+			expr:     "$.Status.Health",
+			expected: []interface{}{"OK"},
+		},
+		{
+			name:     "Index",
+			expr:     "$.Temperatures[1].Name",
+			expected: []interface{}{"CPU 2"},
+		},
+		{
+			name:     "Array Wildcard",
+			expr:     "$.Temperatures[*].ReadingCelsius",
+			expected: []interface{}{float64(40), float64(45)},
+		},
+		{
+			name:     "Quoted Child",
+			expr:     "$.Oem.*['@odata.type']",
+			expected: []interface{}{"#HpeServerChassis"},
+		},
+		{
+			name: "Missing",
+			expr: "$.Status.State",
+		},
+		{
+			name: "Missing Root",
+			expr: "Status.Health",
+			err:  true,
+		},
+		{
+			name: "Unsupported Filter",
+			expr: "$.Temperatures[?(@.Name)]",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := compileJSONPath(test.expr)
+			if test.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, path.find(doc))
+		})
+	}
+}
+
+func Test_Exporter_Metric_Mappings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1":
+			w.Write([]byte(`{"Vendor":"HPE"}`))
+		case "/redfish/v1/Chassis/":
+			w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Chassis/1/"}]}`))
+		case "/redfish/v1/Chassis/1/Sensors/":
+			w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Chassis/1/Sensors/Inlet/"},{"@odata.id":"/redfish/v1/Chassis/1/Sensors/PSU1/"}]}`))
+		case "/redfish/v1/Chassis/1/Sensors/Inlet/":
+			w.Write([]byte(`{"Id":"Inlet","Reading":21.5,"Status":{"Health":"OK"}}`))
+		case "/redfish/v1/Chassis/1/Sensors/PSU1/":
+			w.Write([]byte(`{"Id":"PSU1","Reading":1200,"Status":{"Health":"Critical"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// the bad credentials tests add the test servers host to the ignored list
+	delete(common.IgnoredDevices, "127.0.0.1")
+
+	mappings := []*MetricMapping{
+		{
+			Path:   "/redfish/v1/Chassis/*/Sensors/*",
+			Name:   "redfish_sensor_reading",
+			Help:   "sensor reading",
+			Value:  "$.Reading",
+			Labels: map[string]string{"sensor": "$.Id"},
+		},
+		{
+			Path:     "/redfish/v1/Chassis/*/Sensors/*",
+			Name:     "redfish_sensor_health",
+			Help:     "sensor health 1 = OK, 0 = Critical",
+			Value:    "$.Status.Health",
+			Labels:   map[string]string{"sensor": "$.Id"},
+			ValueMap: map[string]float64{"OK": 1, "Critical": 0},
+		},
+		{
+			Path:    "/redfish/v1/Chassis/*/Sensors/*",
+			Name:    "redfish_sensor_reading_total",
+			Help:    "sensor reading total",
+			Type:    "counter",
+			Value:   "$.Reading",
+			Labels:  map[string]string{"sensor": "$.Id"},
+			Vendors: []string{"hpe"},
+		},
+		{
+			Path:    "/redfish/v1/Chassis/*/Sensors/*",
+			Name:    "redfish_dell_sensor_reading",
+			Help:    "dell sensor reading",
+			Value:   "$.Reading",
+			Vendors: []string{"dell"},
+		},
+	}
+	for _, m := range mappings {
+		assert.Nil(t, m.Load())
+	}
+	MetricMappings.Set(mappings)
+	defer MetricMappings.Set(nil)
+
+	const expected = `
+        # HELP redfish_sensor_health sensor health 1 = OK, 0 = Critical
+        # TYPE redfish_sensor_health gauge
+        redfish_sensor_health{chassisModel="model a",chassisSerialNumber="",sensor="Inlet"} 1
+        redfish_sensor_health{chassisModel="model a",chassisSerialNumber="",sensor="PSU1"} 0
+        # HELP redfish_sensor_reading sensor reading
+        # TYPE redfish_sensor_reading gauge
+        redfish_sensor_reading{chassisModel="model a",chassisSerialNumber="",sensor="Inlet"} 21.5
+        redfish_sensor_reading{chassisModel="model a",chassisSerialNumber="",sensor="PSU1"} 1200
+        # HELP redfish_sensor_reading_total sensor reading total
+        # TYPE redfish_sensor_reading_total counter
+        redfish_sensor_reading_total{chassisModel="model a",chassisSerialNumber="",sensor="Inlet"} 21.5
+        redfish_sensor_reading_total{chassisModel="model a",chassisSerialNumber="",sensor="PSU1"} 1200
+	`
+
+	exp, err := NewPartialExporter(context.Background(), server.URL, "/redfish/v1", "", "model a", nil, []ComponentType{ComponentMappings})
+	if !assert.Nil(t, err) {
+		return
+	}
+	// both sensors are fetched once for all the mappings
+	assert.Len(t, exp.pool.Tasks, 2)
+
+	// the metrics of the mappings are created on first use, like the scrape handlers they need a registry
+	// without pedantic checks
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(exp)
+	assert.Empty(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"redfish_sensor_health", "redfish_sensor_reading", "redfish_sensor_reading_total", "redfish_dell_sensor_reading"))
+}
+
+func Test_MetricMapping_Load_Builtin(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "redfish_up"},
+		{name: "redfish_thermal_fan_speed"},
+		{name: "redfish_energy_joules_total"},
+		{name: "redfish_telemetry_cpu_temp"},
+		{name: "redfish_oem_fan_speed", valid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &MetricMapping{Path: "/redfish/v1/", Name: test.name, Help: "help", Value: "$.Value"}
+			err := m.Load()
+			if test.valid {
+				assert.Nil(t, err)
+				// the name is still free once it was checked
+				assert.Nil(t, m.Load())
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
+
+type testPlugin struct {
+	host string
+}
+
+func (p *testPlugin) Apply(e *Exporter) error {
+	p.host = e.host
+	return nil
+}
+
+func Test_ParsePlugins(t *testing.T) {
+	RegisterPlugin(PluginInfo{Name: "test", Description: "test plugin", New: func() Plugin { return &testPlugin{} }})
+	defer delete(registeredPlugins, "test")
+
+	assert := assert.New(t)
+	assert.Panics(func() { RegisterPlugin(PluginInfo{Name: "test", New: func() Plugin { return &testPlugin{} }}) })
+	assert.Contains(PluginNames(), "test")
+
+	plugs, err := ParsePlugins("")
+	assert.Nil(err)
+	assert.Empty(plugs)
+
+	plugs, err = ParsePlugins(" test,,test")
+	assert.Nil(err)
+	assert.Len(plugs, 1)
+
+	// every scrape gets its own instance of the plugin
+	again, _ := ParsePlugins("test")
+	assert.NotSame(plugs[0], again[0])
+
+	_, err = ParsePlugins("test,unknown")
+	assert.NotNil(err)
+}
+
+func Test_PluginInfo_supports(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(PluginInfo{}.supports("dl360", nil))
+
+	info := PluginInfo{Models: []string{"cisco"}}
+	assert.True(info.supports("Cisco", nil))
+	assert.True(info.supports("", &Vendor{Name: "cisco"}))
+	assert.False(info.supports("dl360", &Vendor{Name: "hpe"}))
+	assert.False(info.supports("", nil))
+}
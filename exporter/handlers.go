@@ -21,48 +21,48 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/middleware/logging"
 	"github.com/comcast/fishymetrics/oem"
+	"go.uber.org/zap"
 )
 
-func handle(exp *Exporter, metricType ...string) []common.Handler {
-	var handlers []common.Handler
-
-	for _, m := range metricType {
-		if m == THERMAL {
-			handlers = append(handlers, exp.exportThermalMetrics)
-		} else if m == POWER {
-			handlers = append(handlers, exp.exportPowerMetrics)
-		} else if m == NVME {
-			handlers = append(handlers, exp.exportNVMeDriveMetrics)
-		} else if m == DISKDRIVE {
-			handlers = append(handlers, exp.exportPhysicalDriveMetrics)
-		} else if m == LOGICALDRIVE {
-			handlers = append(handlers, exp.exportLogicalDriveMetrics)
-		} else if m == UNKNOWN_DRIVE {
-			handlers = append(handlers, exp.exportUnknownDriveMetrics)
-		} else if m == STORAGE_CONTROLLER {
-			handlers = append(handlers, exp.exportStorageControllerMetrics)
-		} else if m == MEMORY {
-			handlers = append(handlers, exp.exportMemoryMetrics)
-		} else if m == MEMORY_SUMMARY {
-			handlers = append(handlers, exp.exportMemorySummaryMetrics)
-		} else if m == FIRMWARE {
-			handlers = append(handlers, exp.exportFirmwareMetrics)
-		} else if m == PROCESSOR {
-			handlers = append(handlers, exp.exportProcessorMetrics)
-		} else if m == STORAGEBATTERY {
-			handlers = append(handlers, exp.exportStorageBattery)
-		} else if m == ILOSELFTEST {
-			handlers = append(handlers, exp.exportIloSelfTest)
-		} else if m == FIRMWAREINVENTORY {
-			handlers = append(handlers, exp.exportFirmwareInventoryMetrics)
-		}
-
-	}
-
-	return handlers
+// parsers export the metrics of each kind of resource body
+var parsers = map[string]func(*Exporter, []byte) error{
+	THERMAL:            (*Exporter).exportThermalMetrics,
+	POWER:              (*Exporter).exportPowerMetrics,
+	NVME:               (*Exporter).exportNVMeDriveMetrics,
+	DISKDRIVE:          (*Exporter).exportPhysicalDriveMetrics,
+	LOGICALDRIVE:       (*Exporter).exportLogicalDriveMetrics,
+	UNKNOWN_DRIVE:      (*Exporter).exportUnknownDriveMetrics,
+	STORAGE_CONTROLLER: (*Exporter).exportStorageControllerMetrics,
+	MEMORY:             (*Exporter).exportMemoryMetrics,
+	MEMORY_SUMMARY:     (*Exporter).exportMemorySummaryMetrics,
+	SYSTEM:             (*Exporter).exportSystemMetrics,
+	FIRMWARE:           (*Exporter).exportFirmwareMetrics,
+	PROCESSOR:          (*Exporter).exportProcessorMetrics,
+	STORAGEBATTERY:     (*Exporter).exportStorageBattery,
+	ILOSELFTEST:        (*Exporter).exportIloSelfTest,
+	MANAGER:            (*Exporter).exportManagerMetrics,
+	MANAGERDIAGNOSTIC:  (*Exporter).exportManagerDiagnosticMetrics,
+	NETWORKPROTOCOL:    (*Exporter).exportNetworkProtocolMetrics,
+	ACCOUNTSERVICE:     (*Exporter).exportAccountServiceMetrics,
+	ACCOUNT:            (*Exporter).exportAccountMetrics,
+	SECUREBOOT:         (*Exporter).exportSecureBootMetrics,
+	TRUSTEDMODULES:     (*Exporter).exportTrustedModulesMetrics,
+	CERTIFICATE:        (*Exporter).exportCertificateMetrics,
+	HTTPSCERTIFICATES:  (*Exporter).exportServedCertificateMetrics,
+	HTTPSCERTIFICATE:   (*Exporter).exportHTTPSCertificateMetrics,
+	TELEMETRY:          (*Exporter).exportTelemetryMetrics,
+	LOGENTRIES:         (*Exporter).exportLogEntryMetrics,
+	FIRMWAREINVENTORY:  (*Exporter).exportFirmwareInventoryMetrics,
+	POWERSUBSYSTEM:     (*Exporter).exportPowerSubsystemMetrics,
+	POWERSUPPLY:        (*Exporter).exportPowerSupplyMetrics,
+	ENERGY:             (*Exporter).exportEnergyMetrics,
+	POWER_REDUNDANCY:   (*Exporter).exportPowerSubsystemRedundancy,
+	THERMALSUBSYSTEM:   (*Exporter).exportThermalSubsystemMetrics,
+	FAN:                (*Exporter).exportFanMetrics,
 }
 
 // exportFirmwareMetrics collects the device metrics in json format and sets the prometheus gauges
@@ -79,6 +79,398 @@ func (e *Exporter) exportFirmwareMetrics(body []byte) error {
 	return nil
 }
 
+// exportManagerMetrics collects the manager health, uptime and clock offset metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportManagerMetrics(body []byte) error {
+	var state float64
+	var mgr oem.Manager
+	var mgrMetrics = (*e.DeviceMetrics)["managerMetrics"]
+	err := json.Unmarshal(body, &mgr)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling ManagerMetrics - %s", err.Error())
+	}
+
+	if mgr.Status.State != "" || mgr.Status.Health != "" {
+		if mgr.Status.State == "Enabled" || mgr.Status.State == "" {
+			if mgr.Status.Health == "OK" {
+				state = OK
+			} else {
+				state = BAD
+			}
+		} else {
+			state = DISABLED
+		}
+		(*mgrMetrics)["managerStatus"].WithLabelValues(mgr.Url, e.ChassisSerialNumber, e.Model).Set(state)
+	}
+
+	fetchedAt := e.managerFetchedAt
+	if fetchedAt.IsZero() {
+		fetchedAt = time.Now()
+	}
+
+	// the timestamps some firmwares report in other formats only skip the clock metrics, the other manager
+	// metrics are still exported
+	bmcTime, bmcTimeOk := e.parseManagerTime("DateTime", mgr.DateTime)
+	if bmcTimeOk {
+		(*mgrMetrics)["managerClockOffset"].WithLabelValues(mgr.Url, e.ChassisSerialNumber, e.Model).Set(bmcTime.Sub(fetchedAt).Seconds())
+	}
+
+	if lastReset, ok := e.parseManagerTime("LastResetTime", mgr.LastResetTime); ok {
+		(*mgrMetrics)["managerLastReset"].WithLabelValues(mgr.Url, e.ChassisSerialNumber, e.Model).Set(float64(lastReset.Unix()))
+
+		// LastResetTime is reported by the BMC clock, so uptime is measured against the BMC DateTime when present
+		now := fetchedAt
+		if bmcTimeOk {
+			now = bmcTime
+		}
+		(*mgrMetrics)["managerUptime"].WithLabelValues(mgr.Url, e.ChassisSerialNumber, e.Model).Set(now.Sub(lastReset).Seconds())
+	}
+
+	return nil
+}
+
+// parseManagerTime parses an RFC3339 timestamp of the manager, the bool return value is false when it is missing
+// or could not be parsed
+func (e *Exporter) parseManagerTime(field, value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		zap.L().Error("error parsing manager "+field, zap.Error(err), zap.String("target", e.host),
+			zap.Any("trace_id", e.ctx.Value(logging.TraceIDKey("traceID"))))
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// exportManagerDiagnosticMetrics collects the ManagerDiagnosticData metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportManagerDiagnosticMetrics(body []byte) error {
+	var diag oem.ManagerDiagnosticData
+	var mgrMetrics = (*e.DeviceMetrics)["managerMetrics"]
+	err := json.Unmarshal(body, &diag)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling ManagerDiagnosticMetrics - %s", err.Error())
+	}
+
+	if total, ok := parseFloat(diag.MemoryStatistics.TotalBytes); ok {
+		(*mgrMetrics)["managerMemoryTotal"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(total)
+	}
+	if used, ok := parseFloat(diag.MemoryStatistics.UsedBytes); ok {
+		(*mgrMetrics)["managerMemoryUsed"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(used)
+	}
+	if available, ok := parseFloat(diag.MemoryStatistics.AvailableBytes); ok {
+		(*mgrMetrics)["managerMemoryAvailable"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(available)
+	}
+	if kernel, ok := parseFloat(diag.ProcessorStatistics.KernelPercent); ok {
+		(*mgrMetrics)["managerCPUKernel"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(kernel)
+	}
+	if user, ok := parseFloat(diag.ProcessorStatistics.UserPercent); ok {
+		(*mgrMetrics)["managerCPUUser"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(user)
+	}
+	if free, ok := parseFloat(diag.FreeStorageSpaceKiB); ok {
+		(*mgrMetrics)["managerFreeStorage"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(free * 1024)
+	}
+	if uptime, ok := parseFloat(diag.ServiceRootUptimeSeconds); ok {
+		(*mgrMetrics)["managerServiceUptime"].WithLabelValues(diag.Url, e.ChassisSerialNumber, e.Model).Set(uptime)
+	}
+
+	return nil
+}
+
+// defaultAccountNames are the factory default BMC user names for the supported vendors
+var defaultAccountNames = map[string]bool{
+	"root":          true,
+	"ADMIN":         true,
+	"Administrator": true,
+	"admin":         true,
+}
+
+// exportNetworkProtocolMetrics collects the manager network protocol metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportNetworkProtocolMetrics(body []byte) error {
+	var np oem.NetworkProtocol
+	var sec = (*e.DeviceMetrics)["securityMetrics"]
+	err := json.Unmarshal(body, &np)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling NetworkProtocolMetrics - %s", err.Error())
+	}
+
+	protocols := []struct {
+		name     string
+		protocol oem.Protocol
+	}{
+		{"HTTP", np.HTTP},
+		{"HTTPS", np.HTTPS},
+		{"IPMI", np.IPMI},
+		{"KVMIP", np.KVMIP},
+		{"SNMP", np.SNMP},
+		{"SSH", np.SSH},
+		{"Telnet", np.Telnet},
+		{"VirtualMedia", np.VirtualMedia},
+	}
+
+	for _, p := range protocols {
+		// skip protocols the BMC does not report on
+		if p.protocol.ProtocolEnabled == nil {
+			continue
+		}
+
+		var port string
+		if v, ok := parseFloat(p.protocol.Port); ok {
+			port = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+
+		state := BAD
+		if *p.protocol.ProtocolEnabled {
+			state = OK
+		}
+		(*sec)["protocolEnabled"].WithLabelValues(p.name, port, e.ChassisSerialNumber, e.Model).Set(state)
+	}
+
+	return nil
+}
+
+// exportAccountServiceMetrics collects the account lockout policy metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportAccountServiceMetrics(body []byte) error {
+	var as oem.AccountService
+	var sec = (*e.DeviceMetrics)["securityMetrics"]
+	err := json.Unmarshal(body, &as)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling AccountServiceMetrics - %s", err.Error())
+	}
+
+	if threshold, ok := parseFloat(as.AccountLockoutThreshold); ok {
+		(*sec)["accountLockoutThreshold"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(threshold)
+	}
+	if duration, ok := parseFloat(as.AccountLockoutDuration); ok {
+		(*sec)["accountLockoutDuration"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(duration)
+	}
+	if length, ok := parseFloat(as.MinPasswordLength); ok {
+		(*sec)["minPasswordLength"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(length)
+	}
+
+	// the account tasks run after this one and increment these gauges
+	(*sec)["accountsEnabled"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(0)
+	(*sec)["defaultAccountEnabled"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(0)
+
+	return nil
+}
+
+// exportAccountMetrics collects a BMC account in json format and updates the enabled account gauges
+func (e *Exporter) exportAccountMetrics(body []byte) error {
+	var acct oem.ManagerAccount
+	var sec = (*e.DeviceMetrics)["securityMetrics"]
+	err := json.Unmarshal(body, &acct)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling AccountMetrics - %s", err.Error())
+	}
+
+	// some BMCs list empty account slots, those have no user name
+	if !acct.Enabled || acct.UserName == "" {
+		return nil
+	}
+
+	(*sec)["accountsEnabled"].WithLabelValues(e.ChassisSerialNumber, e.Model).Inc()
+	if defaultAccountNames[acct.UserName] {
+		(*sec)["defaultAccountEnabled"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(1.0)
+	}
+
+	return nil
+}
+
+// exportSecureBootMetrics collects the UEFI Secure Boot metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportSecureBootMetrics(body []byte) error {
+	var state float64
+	var sb oem.SecureBoot
+	var sec = (*e.DeviceMetrics)["securityMetrics"]
+	err := json.Unmarshal(body, &sb)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling SecureBootMetrics - %s", err.Error())
+	}
+
+	// SecureBootCurrentBoot reflects the state of the current boot, SecureBootEnable only takes effect on the next one
+	if sb.SecureBootCurrentBoot != "" {
+		if sb.SecureBootCurrentBoot == "Enabled" {
+			state = OK
+		} else {
+			state = BAD
+		}
+	} else if sb.SecureBootEnable != nil {
+		if *sb.SecureBootEnable {
+			state = OK
+		} else {
+			state = BAD
+		}
+	} else {
+		return nil
+	}
+
+	(*sec)["secureBootEnabled"].WithLabelValues(sb.SecureBootMode, e.ChassisSerialNumber, e.Model).Set(state)
+
+	return nil
+}
+
+// exportTrustedModulesMetrics collects the TrustedModules from the system in json format and sets the prometheus gauges
+func (e *Exporter) exportTrustedModulesMetrics(body []byte) error {
+	var state float64
+	var sys oem.System
+	var sec = (*e.DeviceMetrics)["securityMetrics"]
+	err := json.Unmarshal(body, &sys)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling TrustedModulesMetrics - %s", err.Error())
+	}
+
+	for _, tm := range sys.TrustedModules {
+		if tm.Status.State == "Enabled" {
+			state = OK
+		} else {
+			state = BAD
+		}
+		(*sec)["trustedModuleEnabled"].WithLabelValues(tm.InterfaceType, tm.FirmwareVersion, e.ChassisSerialNumber, e.Model).Set(state)
+	}
+
+	return nil
+}
+
+// exportCertificateMetrics collects a certificate in json format and sets the prometheus expiry gauge
+func (e *Exporter) exportCertificateMetrics(body []byte) error {
+	_, err := e.exportCertificate(body, "")
+	return err
+}
+
+// exportServedCertificateMetrics sets the prometheus expiry gauge for the certificate the BMC presented during the TLS handshake,
+// the HTTPS certificates compared against it are handled after this
+func (e *Exporter) exportServedCertificateMetrics(body []byte) error {
+	var certMetrics = (*e.DeviceMetrics)["certificateMetrics"]
+
+	leaf := e.tlsRecorder.lastLeaf()
+	if leaf == nil {
+		return nil
+	}
+
+	(*certMetrics)["certificateNotAfter"].WithLabelValues(leaf.Subject.CommonName, leaf.Issuer.CommonName, "Served", e.ChassisSerialNumber, e.Model).Set(float64(leaf.NotAfter.Unix()))
+	(*certMetrics)["certificateServedMatch"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(BAD)
+
+	return nil
+}
+
+// exportHTTPSCertificateMetrics collects a manager HTTPS certificate in json format, sets the prometheus expiry gauge
+// and checks if it is the certificate the BMC is actually serving
+func (e *Exporter) exportHTTPSCertificateMetrics(body []byte) error {
+	var certMetrics = (*e.DeviceMetrics)["certificateMetrics"]
+
+	cert, err := e.exportCertificate(body, "Web")
+	if err != nil {
+		return err
+	}
+
+	leaf := e.tlsRecorder.lastLeaf()
+	if leaf != nil && certificateMatches(cert, leaf) {
+		(*certMetrics)["certificateServedMatch"].WithLabelValues(e.ChassisSerialNumber, e.Model).Set(OK)
+	}
+
+	return nil
+}
+
+// exportTelemetryMetrics collects a telemetry metric report in json format and sets a prometheus gauge
+// named from the MetricId of each of its metric values
+func (e *Exporter) exportTelemetryMetrics(body []byte) error {
+	var mr oem.MetricReport
+	var telemetry = (*e.DeviceMetrics)["telemetryMetrics"]
+	err := json.Unmarshal(body, &mr)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling TelemetryMetrics - %s", err.Error())
+	}
+
+	for _, mv := range mr.MetricValues {
+		value, ok := parseFloat(mv.MetricValue)
+		if !ok {
+			continue
+		}
+
+		name := telemetryMetricName(mv.MetricID)
+		if name == "" {
+			continue
+		}
+
+		gauge, ok := (*telemetry)[name]
+		if !ok {
+			gauge = newServerMetric(name, "Redfish telemetry metric report value of MetricId "+mv.MetricID, nil, []string{"report", "property", "chassisSerialNumber", "chassisModel"})
+			(*telemetry)[name] = gauge
+		}
+
+		// reports that append readings list the same property several times, the newest one is listed last
+		gauge.WithLabelValues(mr.ID, mv.MetricProperty, e.ChassisSerialNumber, e.Model).Set(value)
+	}
+
+	return nil
+}
+
+// exportLogEntryMetrics collects the entries of a log service in json format, sets the prometheus severity counters
+// and forwards the entries created since the previous scrape to the logger
+func (e *Exporter) exportLogEntryMetrics(body []byte) error {
+	var coll oem.LogEntryCollection
+	var logMetrics = (*e.DeviceCounters)["logMetrics"]
+	err := json.Unmarshal(body, &coll)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling LogEntryMetrics - %s", err.Error())
+	}
+
+	// /redfish/v1/Systems/1/LogServices/IML/Entries/ -> IML
+	service := strings.TrimSuffix(strings.TrimSuffix(coll.Url, "/"), "/Entries")
+	service = service[strings.LastIndex(service, "/")+1:]
+
+	newEntries, counts := logEntries.observe(logServiceKey(e.host, coll.Url), coll.Members)
+
+	// counters can only be incremented, the stored totals replace the previous values each scrape
+	for severity, total := range counts {
+		(*logMetrics)["logEntriesTotal"].DeleteLabelValues(service, severity, e.ChassisSerialNumber, e.Model)
+		(*logMetrics)["logEntriesTotal"].WithLabelValues(service, severity, e.ChassisSerialNumber, e.Model).Add(total)
+	}
+
+	for _, entry := range newEntries {
+		zap.L().Info("redfish log entry",
+			zap.String("target", e.host),
+			zap.String("service", service),
+			zap.String("id", entry.ID),
+			zap.String("created", entry.Created),
+			zap.String("severity", logEntrySeverity(entry)),
+			zap.String("message", entry.Message),
+			zap.String("message_id", entry.MessageID),
+			zap.String("sensor_type", entry.SensorType),
+			zap.String("entry_type", entry.EntryType),
+			zap.Any("trace_id", e.ctx.Value(logging.TraceIDKey("traceID"))))
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportCertificate(body []byte, defaultUsage string) (oem.Certificate, error) {
+	var cert oem.Certificate
+	var certMetrics = (*e.DeviceMetrics)["certificateMetrics"]
+	err := json.Unmarshal(body, &cert)
+	if err != nil {
+		return cert, fmt.Errorf("error unmarshalling CertificateMetrics - %s", err.Error())
+	}
+
+	if cert.ValidNotAfter == "" {
+		return cert, nil
+	}
+
+	notAfter, err := time.Parse(time.RFC3339, cert.ValidNotAfter)
+	if err != nil {
+		return cert, fmt.Errorf("error parsing certificate ValidNotAfter - %s", err.Error())
+	}
+
+	usage := strings.Join(cert.CertificateUsageTypes, ",")
+	if usage == "" {
+		usage = defaultUsage
+	}
+
+	(*certMetrics)["certificateNotAfter"].WithLabelValues(certificateName(cert.Subject), certificateName(cert.Issuer), usage, e.ChassisSerialNumber, e.Model).Set(float64(notAfter.Unix()))
+
+	return cert, nil
+}
+
 // exportPowerMetrics collects the power metrics in json format and sets the prometheus gauges
 func (e *Exporter) exportPowerMetrics(body []byte) error {
 	var state float64
@@ -89,7 +481,7 @@ func (e *Exporter) exportPowerMetrics(body []byte) error {
 		return fmt.Errorf("error unmarshalling PowerMetrics - %s", err.Error())
 	}
 
-	for _, pc := range pm.PowerControl.PowerControl {
+	for i, pc := range pm.PowerControl.PowerControl {
 		var watts float64
 		switch pc.PowerConsumedWatts.(type) {
 		case float64:
@@ -112,6 +504,39 @@ func (e *Exporter) exportPowerMetrics(body []byte) error {
 			}
 		}
 		(*pow)["supplyTotalConsumed"].WithLabelValues(pm.Url, e.ChassisSerialNumber, e.Model).Set(watts)
+
+		// the chassis with several PowerControl entries tell them apart by MemberId, their position when it's missing
+		memberID := pc.MemberID
+		if memberID == "" {
+			memberID = strconv.Itoa(i)
+		}
+
+		// power capping and capacity configuration
+		if capacity, ok := parseFloat(pc.PowerCapacityWatts); ok {
+			(*pow)["controlCapacity"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model).Set(capacity)
+		}
+		if limit, ok := parseFloat(pc.PowerLimit.LimitInWatts); ok {
+			(*pow)["controlLimit"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model).Set(limit)
+		}
+		if pc.PowerLimit.LimitException != "" {
+			(*pow)["controlLimitException"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model, pc.PowerLimit.LimitException).Set(1.0)
+		}
+
+		// min/avg/max consumption over the interval reported by the BMC
+		for _, m := range pc.PowerMetrics.PowerMetric {
+			if minWatts, ok := parseFloat(m.MinConsumedWatts); ok {
+				(*pow)["controlMinConsumed"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model).Set(minWatts)
+			}
+			if avgWatts, ok := parseFloat(m.AverageConsumedWatts); ok {
+				(*pow)["controlAvgConsumed"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model).Set(avgWatts)
+			}
+			if maxWatts, ok := parseFloat(m.MaxConsumedWatts); ok {
+				(*pow)["controlMaxConsumed"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model).Set(maxWatts)
+			}
+			if interval, ok := parseFloat(m.IntervalInMin); ok {
+				(*pow)["controlInterval"].WithLabelValues(memberID, e.ChassisSerialNumber, e.Model).Set(interval)
+			}
+		}
 	}
 
 	for _, pv := range pm.Voltages {
@@ -147,7 +572,9 @@ func (e *Exporter) exportPowerMetrics(body []byte) error {
 		(*pow)["voltageStatus"].WithLabelValues(pv.Name, e.ChassisSerialNumber, e.Model).Set(state)
 	}
 
-	for _, ps := range pm.PowerSupplies {
+	e.recordRedundancy(pm.Url, pm.Redundancy)
+
+	for i, ps := range pm.PowerSupplies {
 		// Consider the MemberID the identifier of the power supply. On HPE and Dell platform at least, the index starts at 0
 		bay := ""
 		switch ps.MemberID.(type) {
@@ -189,6 +616,12 @@ func (e *Exporter) exportPowerMetrics(body []byte) error {
 			state = BAD
 		}
 
+		if ps.Url != "" {
+			e.recordMemberHealth(ps.Url, state == OK)
+		} else {
+			e.recordMemberHealth(fmt.Sprintf("%s#/PowerSupplies/%d", pm.Url, i), state == OK)
+		}
+
 		if ps.Status.State != "Absent" {
 			(*pow)["supplyStatus"].WithLabelValues(ps.Name, e.ChassisSerialNumber, e.Model, strings.TrimRight(ps.Manufacturer, " "), strings.TrimRight(ps.SerialNumber, " "), ps.FirmwareVersion, ps.PowerSupplyType, bay, strings.TrimRight(ps.Model, " ")).Set(state)
 
@@ -225,12 +658,144 @@ func (e *Exporter) exportPowerMetrics(body []byte) error {
 					(*pow)["supplyInput"].WithLabelValues(ps.Name, e.ChassisSerialNumber, e.Model, strings.TrimRight(ps.Manufacturer, " "), strings.TrimRight(ps.SerialNumber, " "), ps.FirmwareVersion, ps.PowerSupplyType, bay, strings.TrimRight(ps.Model, " ")).Set(inputWatts)
 				}
 			}
+
+			if capacity, ok := parseFloat(ps.PowerCapacityWatts); ok && capacity > 0 {
+				(*pow)["supplyCapacity"].WithLabelValues(ps.Name, e.ChassisSerialNumber, e.Model, strings.TrimRight(ps.Manufacturer, " "), strings.TrimRight(ps.SerialNumber, " "), ps.FirmwareVersion, ps.PowerSupplyType, bay, strings.TrimRight(ps.Model, " ")).Set(capacity)
+			}
+
+			if efficiency, ok := parseFloat(ps.EfficiencyPercent); ok && efficiency > 0 {
+				(*pow)["supplyEfficiency"].WithLabelValues(ps.Name, e.ChassisSerialNumber, e.Model, strings.TrimRight(ps.Manufacturer, " "), strings.TrimRight(ps.SerialNumber, " "), ps.FirmwareVersion, ps.PowerSupplyType, bay, strings.TrimRight(ps.Model, " ")).Set(efficiency)
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportPowerSubsystemMetrics collects the PowerSubsystem capacity and allocation metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportPowerSubsystemMetrics(body []byte) error {
+	var psub oem.PowerSubsystem
+	var pow = (*e.DeviceMetrics)["powerMetrics"]
+	err := json.Unmarshal(body, &psub)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling PowerSubsystemMetrics - %s", err.Error())
+	}
+
+	if capacity, ok := parseFloat(psub.CapacityWatts); ok {
+		(*pow)["subsystemCapacity"].WithLabelValues(psub.Url, e.ChassisSerialNumber, e.Model).Set(capacity)
+	}
+	if allocated, ok := parseFloat(psub.Allocation.AllocatedWatts); ok {
+		(*pow)["subsystemAllocated"].WithLabelValues(psub.Url, e.ChassisSerialNumber, e.Model).Set(allocated)
+	}
+	if requested, ok := parseFloat(psub.Allocation.RequestedWatts); ok {
+		(*pow)["subsystemRequested"].WithLabelValues(psub.Url, e.ChassisSerialNumber, e.Model).Set(requested)
+	}
+
+	return nil
+}
+
+// exportPowerSubsystemRedundancy collects the PowerSubsystem power supply redundancy groups in json format
+func (e *Exporter) exportPowerSubsystemRedundancy(body []byte) error {
+	var psub oem.PowerSubsystem
+	err := json.Unmarshal(body, &psub)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling PowerSubsystemMetrics - %s", err.Error())
+	}
+
+	e.recordRedundantGroups(psub.Url, "PowerSupplyRedundancy", psub.PowerSupplyRedundancy)
+
+	return nil
+}
+
+// exportPowerSupplyMetrics collects the PowerSubsystem power supply metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportPowerSupplyMetrics(body []byte) error {
+	var state float64
+	var ps oem.PowerSupplyUnit
+	var pow = (*e.DeviceMetrics)["powerMetrics"]
+	err := json.Unmarshal(body, &ps)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling PowerSupplyMetrics - %s", err.Error())
+	}
+
+	if ps.Status.State == "Absent" {
+		e.recordMemberHealth(ps.Url, false)
+		return nil
+	}
+
+	if ps.Status.State == "Enabled" {
+		if ps.Status.Health == "OK" || ps.Status.Health == "" {
+			state = OK
+		} else {
+			state = BAD
+		}
+	} else {
+		state = BAD
+	}
+
+	e.recordMemberHealth(ps.Url, state == OK)
+
+	bay := ps.ID
+	if ps.Location.PartLocation.ServiceLabel != "" {
+		bay = ps.Location.PartLocation.ServiceLabel
+	}
+
+	labels := []string{ps.Name, e.ChassisSerialNumber, e.Model, strings.TrimRight(ps.Manufacturer, " "), strings.TrimRight(ps.SerialNumber, " "), ps.FirmwareVersion, ps.PowerSupplyType, bay, strings.TrimRight(ps.Model, " ")}
+
+	(*pow)["supplyStatus"].WithLabelValues(labels...).Set(state)
+
+	if capacity, ok := parseFloat(ps.PowerCapacityWatts); ok && capacity > 0 {
+		(*pow)["supplyCapacity"].WithLabelValues(labels...).Set(capacity)
+	}
+
+	for _, rating := range ps.EfficiencyRatings {
+		if efficiency, ok := parseFloat(rating.EfficiencyPercent); ok && efficiency > 0 {
+			(*pow)["supplyEfficiency"].WithLabelValues(labels...).Set(efficiency)
+			break
 		}
 	}
 
 	return nil
 }
 
+// exportEnergyMetrics collects the EnergyJoules/EnergykWh readings from EnvironmentMetrics or PowerSupplyMetrics
+// in json format and sets the prometheus energy counter
+func (e *Exporter) exportEnergyMetrics(body []byte) error {
+	var env oem.EnvironmentMetrics
+	var energy = (*e.DeviceCounters)["energyMetrics"]
+	err := json.Unmarshal(body, &env)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling EnergyMetrics - %s", err.Error())
+	}
+
+	// prefer the joules reading when present since it has a finer resolution than kWh
+	var sensor = "EnergyJoules"
+	var scale = 1.0
+	var reading = env.EnergyJoules
+	if _, ok := parseFloat(reading.Reading); !ok {
+		sensor, scale, reading = "EnergykWh", joulesPerKWh, env.EnergykWh
+	}
+	if _, ok := parseFloat(reading.Reading); !ok {
+		sensor, scale, reading = "EnergySensor", joulesPerKWh, env.EnergySensor
+	}
+
+	value, ok := parseFloat(reading.Reading)
+	if !ok || value < 0 {
+		return nil
+	}
+
+	if reading.DataSourceUri != "" {
+		sensor = reading.DataSourceUri
+	}
+
+	total := energyCounters.observe(energyKey{host: e.host, url: env.Url, sensor: sensor}, value, reading.SensorResetTime)
+
+	// counters can only be incremented, the stored total replaces the previous value each scrape
+	(*energy)["energyTotal"].DeleteLabelValues(env.Url, sensor, e.ChassisSerialNumber, e.Model)
+	(*energy)["energyTotal"].WithLabelValues(env.Url, sensor, e.ChassisSerialNumber, e.Model).Add(total * scale)
+
+	return nil
+}
+
 // exportThermalMetrics collects the thermal and fan metrics in json format and sets the prometheus gauges
 func (e *Exporter) exportThermalMetrics(body []byte) error {
 
@@ -251,8 +816,16 @@ func (e *Exporter) exportThermalMetrics(body []byte) error {
 		(*therm)["thermalSummary"].WithLabelValues(tm.Url, e.ChassisSerialNumber, e.Model).Set(state)
 	}
 
+	e.recordRedundancy(tm.Url, tm.Redundancy)
+
 	// Iterate through fans
-	for _, fan := range tm.Fans {
+	for i, fan := range tm.Fans {
+		fanUrl := fan.Url
+		if fanUrl == "" {
+			fanUrl = fmt.Sprintf("%s#/Fans/%d", tm.Url, i)
+		}
+		e.recordMemberHealth(fanUrl, fan.Status.State == "Enabled" && fan.Status.Health == "OK")
+
 		// Check fan status and convert string to numeric values
 		if fan.Status.State == "Enabled" {
 			var fanSpeed float64
@@ -326,6 +899,50 @@ func (e *Exporter) exportThermalMetrics(body []byte) error {
 	return nil
 }
 
+// exportThermalSubsystemMetrics collects the ThermalSubsystem fan redundancy groups in json format
+func (e *Exporter) exportThermalSubsystemMetrics(body []byte) error {
+	var tsub oem.ThermalSubsystem
+	err := json.Unmarshal(body, &tsub)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling ThermalSubsystemMetrics - %s", err.Error())
+	}
+
+	e.recordRedundantGroups(tsub.Url, "FanRedundancy", tsub.FanRedundancy)
+
+	return nil
+}
+
+// exportFanMetrics collects the ThermalSubsystem fan metrics in json format and sets the prometheus gauges
+func (e *Exporter) exportFanMetrics(body []byte) error {
+	var state float64
+	var fan oem.ThermalFan
+	var therm = (*e.DeviceMetrics)["thermalMetrics"]
+	err := json.Unmarshal(body, &fan)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling FanMetrics - %s", err.Error())
+	}
+
+	if fan.Status.State != "Enabled" {
+		e.recordMemberHealth(fan.Url, false)
+		return nil
+	}
+
+	if fan.Status.Health == "OK" {
+		state = OK
+	} else {
+		state = BAD
+	}
+
+	e.recordMemberHealth(fan.Url, state == OK)
+
+	if speed, ok := parseFloat(fan.SpeedPercent.Reading); ok {
+		(*therm)["fanSpeed"].WithLabelValues(fan.Name, e.ChassisSerialNumber, e.Model).Set(speed)
+	}
+	(*therm)["fanStatus"].WithLabelValues(fan.Name, e.ChassisSerialNumber, e.Model).Set(state)
+
+	return nil
+}
+
 // exportPhysicalDriveMetrics collects the physical drive metrics in json format and sets the prometheus gauges
 func (e *Exporter) exportPhysicalDriveMetrics(body []byte) error {
 
@@ -562,6 +1179,80 @@ func (e *Exporter) exportMemorySummaryMetrics(body []byte) error {
 	return nil
 }
 
+// exportSystemMetrics collects the host power state, boot progress, POST state, indicator LED and health rollup
+// in json format and sets the prometheus gauges
+func (e *Exporter) exportSystemMetrics(body []byte) error {
+	var state float64
+	var sys oem.System
+	var sysMetrics = (*e.DeviceMetrics)["systemMetrics"]
+	err := json.Unmarshal(body, &sys)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling SystemMetrics - %s", err.Error())
+	}
+
+	if sys.PowerState != "" {
+		if sys.PowerState == "On" {
+			state = OK
+		} else {
+			state = BAD
+		}
+		(*sysMetrics)["powerState"].WithLabelValues(e.ChassisSerialNumber, e.Model, sys.PowerState).Set(state)
+	}
+
+	if sys.BootProgress.LastState != "" {
+		lastState := sys.BootProgress.LastState
+		if lastState == "OEM" && sys.BootProgress.OemLastState != "" {
+			lastState = sys.BootProgress.OemLastState
+		}
+		if sys.BootProgress.LastState == "OSRunning" {
+			state = OK
+		} else {
+			state = BAD
+		}
+		(*sysMetrics)["bootProgress"].WithLabelValues(e.ChassisSerialNumber, e.Model, lastState).Set(state)
+	}
+
+	// HPE reports the POST state in the Oem section, older firmware uses the Hp key
+	postState := sys.Oem.Hpe.PostState
+	if postState == "" {
+		postState = sys.Oem.Hp.PostState
+	}
+	if postState != "" {
+		if postState == "FinishedPost" {
+			state = OK
+		} else {
+			state = BAD
+		}
+		(*sysMetrics)["postState"].WithLabelValues(e.ChassisSerialNumber, e.Model, postState).Set(state)
+	}
+
+	// LocationIndicatorActive replaces the deprecated IndicatorLED property
+	if sys.LocationIndicatorActive != nil {
+		state = BAD
+		if *sys.LocationIndicatorActive {
+			state = OK
+		}
+		(*sysMetrics)["indicatorActive"].WithLabelValues(e.ChassisSerialNumber, e.Model, sys.IndicatorLED).Set(state)
+	} else if sys.IndicatorLED != "" {
+		state = BAD
+		if sys.IndicatorLED == "Lit" || sys.IndicatorLED == "Blinking" {
+			state = OK
+		}
+		(*sysMetrics)["indicatorActive"].WithLabelValues(e.ChassisSerialNumber, e.Model, sys.IndicatorLED).Set(state)
+	}
+
+	if sys.Status.HealthRollup != "" {
+		if sys.Status.HealthRollup == "OK" {
+			state = OK
+		} else {
+			state = BAD
+		}
+		(*sysMetrics)["healthRollup"].WithLabelValues(e.ChassisSerialNumber, e.Model, sys.Status.HealthRollup).Set(state)
+	}
+
+	return nil
+}
+
 // exportStorageBattery collects the smart storage battery metrics in json format and sets the prometheus gauge
 func (e *Exporter) exportStorageBattery(body []byte) error {
 
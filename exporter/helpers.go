@@ -22,6 +22,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/comcast/fishymetrics/common"
 	"github.com/comcast/fishymetrics/middleware/logging"
@@ -98,6 +102,20 @@ func getSystemEndpoints(chassisUrls []string, host string, profile string, clien
 			}
 		}
 
+		if chas.PowerSubsystem.URL != "" {
+			url := appendSlash(chas.PowerSubsystem.URL)
+			if checkUnique(sysEnd.powerSubsystem, url) {
+				sysEnd.powerSubsystem = append(sysEnd.powerSubsystem, url)
+			}
+		}
+
+		if chas.EnvironmentMetrics.URL != "" {
+			url := appendSlash(chas.EnvironmentMetrics.URL)
+			if checkUnique(sysEnd.environment, url) {
+				sysEnd.environment = append(sysEnd.environment, url)
+			}
+		}
+
 		if chas.ThermalAlt.URL != "" {
 			url := appendSlash(chas.ThermalAlt.URL)
 			if checkUnique(sysEnd.thermal, url) {
@@ -105,6 +123,13 @@ func getSystemEndpoints(chassisUrls []string, host string, profile string, clien
 			}
 		}
 
+		if chas.ThermalSubsystem.URL != "" {
+			url := appendSlash(chas.ThermalSubsystem.URL)
+			if checkUnique(sysEnd.thermalSubsystem, url) {
+				sysEnd.thermalSubsystem = append(sysEnd.thermalSubsystem, url)
+			}
+		}
+
 		// if power and thermal endpoints are not found in main level, check the nested results in Links/links
 		if len(sysEnd.power) == 0 {
 			for _, power := range chas.Links.Power.LinksURLSlice {
@@ -211,11 +236,11 @@ func getSystemsMetadata(url, host string, profile string, client *retryablehttp.
 	return sys, nil
 }
 
-func getDIMMEndpoints(url, host string, profile string, client *retryablehttp.Client) (oem.Collection, error) {
+func getDIMMEndpoints(url, host string, profile string, client *retryablehttp.Client, q *queryOptions) (oem.Collection, error) {
 	var dimms oem.Collection
 
-	// Use centralized HTTP client with credential rotation
-	fetch := common.Fetch(url, host, profile, client)
+	// Use centralized HTTP client with credential rotation, the DIMMs are expanded when supported
+	fetch := q.collection(url, host, profile, client)
 	body, err := fetch()
 	if err != nil {
 		if errors.Is(err, common.ErrInvalidCredential) {
@@ -235,11 +260,9 @@ func getDIMMEndpoints(url, host string, profile string, client *retryablehttp.Cl
 // The getDriveEndpoint function is used in a recursive fashion to get the body response
 // of any type of drive, NVMe, Physical DiskDrives, or Logical Drives, using the GenericDrive struct
 // This is used to find the final drive endpoints to append to the task pool for final scraping.
-func getDriveEndpoint(url, host string, profile string, client *retryablehttp.Client) (oem.GenericDrive, error) {
+func getDriveEndpoint(fetch func() ([]byte, error)) (oem.GenericDrive, error) {
 	var drive oem.GenericDrive
 
-	// Use centralized HTTP client with credential rotation
-	fetch := common.Fetch(url, host, profile, client)
 	body, err := fetch()
 	if err != nil {
 		if errors.Is(err, common.ErrInvalidCredential) {
@@ -256,11 +279,11 @@ func getDriveEndpoint(url, host string, profile string, client *retryablehttp.Cl
 	return drive, nil
 }
 
-func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, profile string, client *retryablehttp.Client, excludes Excludes) (DriveEndpoints, error) {
+func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, profile string, client *retryablehttp.Client, excludes Excludes, q *queryOptions) (DriveEndpoints, error) {
 	var driveEndpoints DriveEndpoints
 
 	// Get initial JSON return of /redfish/v1/Systems/XXXX/SmartStorage/ArrayControllers/ set to output
-	driveResp, err := getDriveEndpoint(initialUrl, host, profile, client)
+	driveResp, err := getDriveEndpoint(q.collection(initialUrl, host, profile, client))
 	if err != nil {
 		log.Error("api call "+initialUrl+" failed - ", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 		return driveEndpoints, err
@@ -270,7 +293,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 	for _, member := range driveResp.Members {
 		// for each ArrayController URL, get the JSON object
 		// /redfish/v1/Systems/XXXX/SmartStorage/ArrayControllers/X/
-		arrayCtrlResp, err := getDriveEndpoint(fqdn+member.URL, host, profile, client)
+		arrayCtrlResp, err := getDriveEndpoint(q.member(fqdn+member.URL, host, profile, client, ""))
 		if err != nil {
 			log.Error("api call "+fqdn+member.URL+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 			return driveEndpoints, err
@@ -293,7 +316,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 			if len(arrayCtrlResp.Volumes.LinksURLSlice) > 0 {
 				for _, volume := range arrayCtrlResp.Volumes.LinksURLSlice {
 					url := appendSlash(volume)
-					volumeOutput, err := getDriveEndpoint(fqdn+url, host, profile, client)
+					volumeOutput, err := getDriveEndpoint(q.collection(fqdn+url, host, profile, client))
 					if err != nil {
 						log.Error("api call "+fqdn+url+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 						return driveEndpoints, err
@@ -310,7 +333,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 			}
 
 			if arrayCtrlResp.Controllers.URL != "" {
-				controllerOutput, err := getDriveEndpoint(fqdn+arrayCtrlResp.Controllers.URL, host, profile, client)
+				controllerOutput, err := getDriveEndpoint(q.collection(fqdn+arrayCtrlResp.Controllers.URL, host, profile, client))
 				if err != nil {
 					log.Error("api call "+fqdn+arrayCtrlResp.Controllers.URL+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 					return driveEndpoints, err
@@ -328,7 +351,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 		// all other servers apart from iLO6
 		// If LogicalDrives is present, parse logical drive endpoint until all urls are found
 		if arrayCtrlResp.LinksUpper.LogicalDrives.URL != "" {
-			logicalDriveOutput, err := getDriveEndpoint(fqdn+arrayCtrlResp.LinksUpper.LogicalDrives.URL, host, profile, client)
+			logicalDriveOutput, err := getDriveEndpoint(q.collection(fqdn+arrayCtrlResp.LinksUpper.LogicalDrives.URL, host, profile, client))
 			if err != nil {
 				log.Error("api call "+fqdn+arrayCtrlResp.LinksUpper.LogicalDrives.URL+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 				return driveEndpoints, err
@@ -343,7 +366,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 
 		// If PhysicalDrives is present, parse physical drive endpoint until all urls are found
 		if arrayCtrlResp.LinksUpper.PhysicalDrives.URL != "" {
-			physicalDriveOutput, err := getDriveEndpoint(fqdn+arrayCtrlResp.LinksUpper.PhysicalDrives.URL, host, profile, client)
+			physicalDriveOutput, err := getDriveEndpoint(q.collection(fqdn+arrayCtrlResp.LinksUpper.PhysicalDrives.URL, host, profile, client))
 			if err != nil {
 				log.Error("api call "+fqdn+arrayCtrlResp.LinksUpper.PhysicalDrives.URL+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 				return driveEndpoints, err
@@ -356,7 +379,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 
 		// If LogicalDrives is present, parse logical drive endpoint until all urls are found
 		if arrayCtrlResp.LinksLower.LogicalDrives.URL != "" {
-			logicalDriveOutput, err := getDriveEndpoint(fqdn+arrayCtrlResp.LinksLower.LogicalDrives.URL, host, profile, client)
+			logicalDriveOutput, err := getDriveEndpoint(q.collection(fqdn+arrayCtrlResp.LinksLower.LogicalDrives.URL, host, profile, client))
 			if err != nil {
 				log.Error("api call "+fqdn+arrayCtrlResp.LinksLower.LogicalDrives.URL+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 				return driveEndpoints, err
@@ -371,7 +394,7 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 
 		// If PhysicalDrives is present, parse physical drive endpoint until all urls are found
 		if arrayCtrlResp.LinksLower.PhysicalDrives.URL != "" {
-			physicalDriveOutput, err := getDriveEndpoint(fqdn+arrayCtrlResp.LinksLower.PhysicalDrives.URL, host, profile, client)
+			physicalDriveOutput, err := getDriveEndpoint(q.collection(fqdn+arrayCtrlResp.LinksLower.PhysicalDrives.URL, host, profile, client))
 			if err != nil {
 				log.Error("api call "+fqdn+arrayCtrlResp.LinksLower.PhysicalDrives.URL+" failed", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
 				return driveEndpoints, err
@@ -386,11 +409,11 @@ func getAllDriveEndpoints(ctx context.Context, fqdn, initialUrl, host string, pr
 	return driveEndpoints, nil
 }
 
-func getProcessorEndpoints(url, host string, profile string, client *retryablehttp.Client) (oem.Collection, error) {
+func getProcessorEndpoints(url, host string, profile string, client *retryablehttp.Client, q *queryOptions) (oem.Collection, error) {
 	var processors oem.Collection
 
-	// Use centralized HTTP client with credential rotation
-	fetch := common.Fetch(url, host, profile, client)
+	// Use centralized HTTP client with credential rotation, the processors are expanded when supported
+	fetch := q.collection(url, host, profile, client)
 	body, err := fetch()
 	if err != nil {
 		if errors.Is(err, common.ErrInvalidCredential) {
@@ -407,6 +430,240 @@ func getProcessorEndpoints(url, host string, profile string, client *retryableht
 	return processors, nil
 }
 
+// getPowerSupplyEndpoints returns the power supply member urls from the PowerSubsystem/PowerSupplies collection
+func getPowerSupplyEndpoints(fqdn, url, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var ps oem.PowerSubsystem
+	var urls []string
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return urls, common.ErrInvalidCredential
+		}
+		return urls, fmt.Errorf("error fetching power subsystem: %w", err)
+	}
+
+	err = json.Unmarshal(body, &ps)
+	if err != nil {
+		return urls, fmt.Errorf("error unmarshalling PowerSubsystem struct - %s", err.Error())
+	}
+
+	if ps.PowerSupplies.URL == "" {
+		return urls, nil
+	}
+
+	return getMemberUrls(fqdn+appendSlash(ps.PowerSupplies.URL), host, profile, client)
+}
+
+// getFanEndpoints returns the fan member urls from the ThermalSubsystem/Fans collection
+func getFanEndpoints(fqdn, url, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var ts oem.ThermalSubsystem
+	var urls []string
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return urls, common.ErrInvalidCredential
+		}
+		return urls, fmt.Errorf("error fetching thermal subsystem: %w", err)
+	}
+
+	err = json.Unmarshal(body, &ts)
+	if err != nil {
+		return urls, fmt.Errorf("error unmarshalling ThermalSubsystem struct - %s", err.Error())
+	}
+
+	if ts.Fans.URL == "" {
+		return urls, nil
+	}
+
+	return getMemberUrls(fqdn+appendSlash(ts.Fans.URL), host, profile, client)
+}
+
+// getManagerMetadata returns the manager resource, used to find the links to the ManagerDiagnosticData and NetworkProtocol resources
+func getManagerMetadata(url, host string, profile string, client *retryablehttp.Client) (oem.Manager, error) {
+	var mgr oem.Manager
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return mgr, common.ErrInvalidCredential
+		}
+		return mgr, fmt.Errorf("error fetching manager: %w", err)
+	}
+
+	err = json.Unmarshal(body, &mgr)
+	if err != nil {
+		return mgr, fmt.Errorf("error unmarshalling Manager struct - %s", err.Error())
+	}
+
+	return mgr, nil
+}
+
+// getCertificateLocationEndpoints returns the certificate urls listed in the CertificateService/CertificateLocations resource
+func getCertificateLocationEndpoints(fqdn, url, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var cs oem.CertificateService
+	var locations oem.CertificateLocations
+	var urls []string
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return urls, common.ErrInvalidCredential
+		}
+		return urls, fmt.Errorf("error fetching certificate service: %w", err)
+	}
+
+	err = json.Unmarshal(body, &cs)
+	if err != nil {
+		return urls, fmt.Errorf("error unmarshalling CertificateService struct - %s", err.Error())
+	}
+
+	if cs.CertificateLocations.URL == "" {
+		return urls, nil
+	}
+
+	fetch = common.Fetch(fqdn+appendSlash(cs.CertificateLocations.URL), host, profile, client)
+	body, err = fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return urls, common.ErrInvalidCredential
+		}
+		return urls, fmt.Errorf("error fetching certificate locations: %w", err)
+	}
+
+	err = json.Unmarshal(body, &locations)
+	if err != nil {
+		return urls, fmt.Errorf("error unmarshalling CertificateLocations struct - %s", err.Error())
+	}
+
+	for _, cert := range locations.Links.Certificates {
+		urls = append(urls, appendSlash(cert.URL))
+	}
+
+	return urls, nil
+}
+
+// getHTTPSCertificatesEndpoint returns the url of the HTTPS certificates collection from the manager NetworkProtocol resource
+func getHTTPSCertificatesEndpoint(url, host string, profile string, client *retryablehttp.Client) (string, error) {
+	var np oem.NetworkProtocol
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return "", common.ErrInvalidCredential
+		}
+		return "", fmt.Errorf("error fetching network protocol: %w", err)
+	}
+
+	err = json.Unmarshal(body, &np)
+	if err != nil {
+		return "", fmt.Errorf("error unmarshalling NetworkProtocol struct - %s", err.Error())
+	}
+
+	if np.HTTPS.Certificates.URL == "" {
+		return "", nil
+	}
+
+	return appendSlash(np.HTTPS.Certificates.URL), nil
+}
+
+// getAccountEndpoints returns the account member urls from the AccountService/Accounts collection
+func getAccountEndpoints(fqdn, url, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var as oem.AccountService
+	var urls []string
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return urls, common.ErrInvalidCredential
+		}
+		return urls, fmt.Errorf("error fetching account service: %w", err)
+	}
+
+	err = json.Unmarshal(body, &as)
+	if err != nil {
+		return urls, fmt.Errorf("error unmarshalling AccountService struct - %s", err.Error())
+	}
+
+	if as.Accounts.URL == "" {
+		return urls, nil
+	}
+
+	return getMemberUrls(fqdn+appendSlash(as.Accounts.URL), host, profile, client)
+}
+
+// fetchWithTime wraps a fetch function and records the midpoint of the request, handlers only run once
+// every task is done so this keeps the manager clock offset from including the time spent on the other tasks.
+// The BMC reads its clock while the request is in flight, the midpoint halves the error of the request latency
+func fetchWithTime(fetch func() ([]byte, error), fetchedAt *time.Time) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		start := time.Now()
+		body, err := fetch()
+		*fetchedAt = start.Add(time.Since(start) / 2)
+		return body, err
+	}
+}
+
+// getPowerSupplyMetricsEndpoints returns the PowerSupplyMetrics urls for each of the power supply urls provided
+func getPowerSupplyMetricsEndpoints(fqdn string, psuUrls []string, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var urls []string
+
+	for _, url := range psuUrls {
+		var ps oem.PowerSupplyUnit
+		// Use centralized HTTP client with credential rotation
+		fetch := common.Fetch(fqdn+url, host, profile, client)
+		body, err := fetch()
+		if err != nil {
+			if errors.Is(err, common.ErrInvalidCredential) {
+				return urls, common.ErrInvalidCredential
+			}
+			return urls, fmt.Errorf("error fetching power supply: %w", err)
+		}
+
+		err = json.Unmarshal(body, &ps)
+		if err != nil {
+			return urls, fmt.Errorf("error unmarshalling PowerSupplyUnit struct - %s", err.Error())
+		}
+
+		if ps.Metrics.URL != "" {
+			urls = append(urls, appendSlash(ps.Metrics.URL))
+		}
+	}
+
+	return urls, nil
+}
+
+// parseFloat converts the loosely typed numeric values returned by redfish APIs into a float64,
+// the bool return value is false if the value is missing or could not be parsed
+func parseFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
 // appendSlash appends a slash to the end of a URL if it does not already have one
 func appendSlash(url string) string {
 	if url[len(url)-1] != '/' {
@@ -468,3 +725,207 @@ func GetFirmwareInventoryURL(sysResp oem.System) string {
 		sysResp.Oem.Hp.LinksLower.FirmwareInventory.URL,
 	)
 }
+
+// getLogServiceEntriesEndpoints returns the Entries urls of every LogService in the LogServices collection provided
+func getLogServiceEntriesEndpoints(fqdn, url, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var urls []string
+
+	serviceUrls, err := getMemberUrls(url, host, profile, client)
+	if err != nil {
+		return urls, err
+	}
+
+	for _, serviceUrl := range serviceUrls {
+		var svc oem.LogService
+
+		// Use centralized HTTP client with credential rotation
+		fetch := common.Fetch(fqdn+serviceUrl, host, profile, client)
+		body, err := fetch()
+		if err != nil {
+			if errors.Is(err, common.ErrInvalidCredential) {
+				return urls, common.ErrInvalidCredential
+			}
+			return urls, fmt.Errorf("error fetching log service: %w", err)
+		}
+
+		err = json.Unmarshal(body, &svc)
+		if err != nil {
+			return urls, fmt.Errorf("error unmarshalling LogService struct - %s", err.Error())
+		}
+
+		if svc.Entries.URL != "" {
+			urls = append(urls, appendSlash(svc.Entries.URL))
+		}
+	}
+
+	return urls, nil
+}
+
+// fetchLogEntries returns a fetch function that follows Members@odata.nextLink and returns every page of the
+// Entries collection as a single collection. Paging stops at logMaxPages or, when the BMC lists the newest
+// entries first, as soon as a page reaches the high-water mark recorded for the service. When the BMC lists
+// the oldest entries first and supports $skip, the pages are read from the end of the collection so the new
+// entries are reached.
+func fetchLogEntries(fqdn, url, key, host string, profile string, client *retryablehttp.Client, skip bool) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		var entries oem.LogEntryCollection
+
+		mark, seen := logEntries.highWaterMark(key)
+		next := fqdn + url
+		for page := 0; page < logMaxPages && next != ""; page++ {
+			coll, err := fetchLogEntriesPage(next, host, profile, client)
+			if err != nil {
+				return nil, err
+			}
+
+			n := len(coll.Members)
+			if page == 0 {
+				entries.MembersCount = coll.MembersCount
+
+				// the remaining pages can't reach the end of an oldest first collection, skip to its last pages
+				if start := coll.MembersCount - (logMaxPages-1)*n; skip && coll.NextLink != "" && n > 1 && start > n &&
+					logEntryCreated(coll.Members[0]).Before(logEntryCreated(coll.Members[n-1])) {
+					last, err := fetchLogEntriesPage(fqdn+url+"?$skip="+strconv.Itoa(start), host, profile, client)
+					if err == nil {
+						coll = last
+						n = len(coll.Members)
+					}
+				}
+			}
+			entries.Members = append(entries.Members, coll.Members...)
+
+			next = ""
+			if coll.NextLink != "" {
+				next = fqdn + coll.NextLink
+			}
+
+			if seen && n > 1 && !logEntryCreated(coll.Members[0]).Before(logEntryCreated(coll.Members[n-1])) &&
+				logEntryCreated(coll.Members[n-1]).Before(mark) {
+				break
+			}
+		}
+
+		// the handler keys the high-water mark on the url the entries were fetched from
+		entries.Url = url
+
+		return json.Marshal(entries)
+	}
+}
+
+// fetchLogEntriesPage returns a page of a LogService Entries collection
+func fetchLogEntriesPage(url, host string, profile string, client *retryablehttp.Client) (oem.LogEntryCollection, error) {
+	var coll oem.LogEntryCollection
+
+	// Use centralized HTTP client with credential rotation
+	body, err := common.Fetch(url, host, profile, client)()
+	if err != nil {
+		return coll, err
+	}
+
+	err = json.Unmarshal(body, &coll)
+	if err != nil {
+		return coll, fmt.Errorf("error unmarshalling LogEntryCollection struct - %s", err.Error())
+	}
+
+	return coll, nil
+}
+
+// getMetricReportEndpoints returns the urls of the metric reports generated by the enabled MetricReportDefinitions,
+// the MetricReports collection is used when the BMC does not list the definitions
+func getMetricReportEndpoints(fqdn, url, host string, profile string, client *retryablehttp.Client) ([]string, error) {
+	var ts oem.TelemetryService
+	var urls []string
+
+	// Use centralized HTTP client with credential rotation
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return urls, common.ErrInvalidCredential
+		}
+		return urls, fmt.Errorf("error fetching telemetry service: %w", err)
+	}
+
+	err = json.Unmarshal(body, &ts)
+	if err != nil {
+		return urls, fmt.Errorf("error unmarshalling TelemetryService struct - %s", err.Error())
+	}
+
+	if (ts.ServiceEnabled != nil && !*ts.ServiceEnabled) || ts.Status.State == "Disabled" {
+		return urls, nil
+	}
+
+	if ts.MetricReportDefinitions.URL == "" {
+		if ts.MetricReports.URL == "" {
+			return urls, nil
+		}
+		return getMemberUrls(fqdn+appendSlash(ts.MetricReports.URL), host, profile, client)
+	}
+
+	definitions, err := getMemberUrls(fqdn+appendSlash(ts.MetricReportDefinitions.URL), host, profile, client)
+	if err != nil {
+		return urls, err
+	}
+
+	for _, definition := range definitions {
+		var mrd oem.MetricReportDefinition
+
+		// Use centralized HTTP client with credential rotation
+		fetch := common.Fetch(fqdn+definition, host, profile, client)
+		body, err := fetch()
+		if err != nil {
+			if errors.Is(err, common.ErrInvalidCredential) {
+				return urls, common.ErrInvalidCredential
+			}
+			return urls, fmt.Errorf("error fetching metric report definition: %w", err)
+		}
+
+		err = json.Unmarshal(body, &mrd)
+		if err != nil {
+			return urls, fmt.Errorf("error unmarshalling MetricReportDefinition struct - %s", err.Error())
+		}
+
+		if (mrd.MetricReportDefinitionEnabled != nil && !*mrd.MetricReportDefinitionEnabled) || mrd.Status.State == "Disabled" {
+			continue
+		}
+
+		if mrd.MetricReport.URL != "" {
+			urls = append(urls, appendSlash(mrd.MetricReport.URL))
+		}
+	}
+
+	return urls, nil
+}
+
+// telemetryMetricName returns the prometheus metric name of a telemetry MetricId, i.e. CPUTemp -> redfish_telemetry_cpu_temp
+func telemetryMetricName(metricID string) string {
+	var b strings.Builder
+	runes := []rune(metricID)
+
+	for i, r := range runes {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsUpper(r):
+			// start a new word on a lower to upper case change and at the last capital of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLower(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	name := b.String()
+	for strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", "_")
+	}
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return ""
+	}
+
+	return "redfish_telemetry_" + name
+}
@@ -15,12 +15,14 @@ import (
 // NewHTTPClient builds a retryablehttp client honoring proxy from context override,
 // otherwise falling back to standard HTTP(S)_PROXY/NO_PROXY environment variables.
 func NewHTTPClient(ctx context.Context) *retryablehttp.Client {
+    opts := scrapeOptionsFromContext(ctx)
+
     tr := &http.Transport{
         Dial: (&net.Dialer{Timeout: 3 * time.Second}).Dial,
         Proxy:                 http.ProxyFromEnvironment,
-        MaxIdleConns:          1,
-        MaxConnsPerHost:       1,
-        MaxIdleConnsPerHost:   1,
+        MaxIdleConns:          opts.concurrency(),
+        MaxConnsPerHost:       opts.concurrency(),
+        MaxIdleConnsPerHost:   opts.concurrency(),
         IdleConnTimeout:       90 * time.Second,
         ExpectContinueTimeout: 1 * time.Second,
         TLSClientConfig: &tls.Config{
@@ -30,6 +32,21 @@ func NewHTTPClient(ctx context.Context) *retryablehttp.Client {
         TLSHandshakeTimeout: 10 * time.Second,
     }
 
+    opts.TLS.Apply(tr.TLSClientConfig)
+
+    // record the certificate the BMC serves so it can be compared to the ones reported by the CertificateService
+    if rec := tlsRecorderFromContext(ctx); rec != nil {
+        verify := tr.TLSClientConfig.VerifyConnection
+        tr.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+            if verify != nil {
+                if err := verify(cs); err != nil {
+                    return err
+                }
+            }
+            return rec.record(cs)
+        }
+    }
+
     if p := proxyURLFromContext(ctx); p != nil {
         proxy := *p
         tr.Proxy = func(r *http.Request) (*url.URL, error) { return &proxy, nil }
@@ -39,6 +56,9 @@ func NewHTTPClient(ctx context.Context) *retryablehttp.Client {
     retryClient.CheckRetry = retryablehttp.ErrorPropagatedRetryPolicy
     retryClient.HTTPClient.Transport = tr
     retryClient.HTTPClient.Timeout = 30 * time.Second
+    if opts.Timeout > 0 {
+        retryClient.HTTPClient.Timeout = opts.Timeout
+    }
     retryClient.Logger = nil
     retryClient.RetryWaitMin = 2 * time.Second
     retryClient.RetryWaitMax = 2 * time.Second
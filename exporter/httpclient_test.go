@@ -1,6 +1,10 @@
 package exporter
 
 import (
+    "bytes"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/hex"
     "io"
     "net/http"
     "net/http/httptest"
@@ -8,6 +12,7 @@ import (
     "strings"
     "sync/atomic"
     "testing"
+    "time"
 
     "github.com/comcast/fishymetrics/common"
 )
@@ -89,3 +94,107 @@ func Test_NewHTTPClient_OverrideBeatsNoProxy(t *testing.T) {
     }
 }
 
+// Ensure the leaf certificate served during the TLS handshake is recorded.
+func Test_NewHTTPClient_RecordsServedCertificate(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        _, _ = w.Write([]byte(`{"ok":true}`))
+    }))
+    defer server.Close()
+
+    rec := &tlsRecorder{}
+    client := NewHTTPClient(withTLSRecorder(nil, rec))
+
+    // trust the test server certificate so the handshake succeeds with verification enabled
+    roots := x509.NewCertPool()
+    roots.AddCert(server.Certificate())
+    client.HTTPClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+
+    u, _ := url.Parse(server.URL)
+    req, err := common.BuildRequest(server.URL+"/redfish/v1/", u.Host)
+    if err != nil {
+        t.Fatalf("BuildRequest error: %v", err)
+    }
+
+    resp, err := common.DoRequest(client, req)
+    if err != nil {
+        t.Fatalf("DoRequest error: %v", err)
+    }
+    defer common.EmptyAndCloseBody(resp)
+
+    leaf := rec.lastLeaf()
+    if leaf == nil {
+        t.Fatalf("no served certificate recorded")
+    }
+    if !bytes.Equal(leaf.Raw, server.Certificate().Raw) {
+        t.Fatalf("recorded certificate does not match the served certificate")
+    }
+}
+
+// Ensure the scrape options of the context override the client defaults.
+func Test_NewHTTPClient_ScrapeOptions(t *testing.T) {
+    insecure := true
+    ctx := WithScrapeOptions(nil, ScrapeOptions{Timeout: 5 * time.Second, Concurrency: 3, TLS: &common.TLSSettings{InsecureSkipVerify: &insecure}})
+    client := NewHTTPClient(ctx)
+
+    tr := client.HTTPClient.Transport.(*http.Transport)
+    if client.HTTPClient.Timeout != 5*time.Second || tr.MaxConnsPerHost != 3 || !tr.TLSClientConfig.InsecureSkipVerify {
+        t.Fatalf("timeout = %s max conns = %d insecure = %v", client.HTTPClient.Timeout, tr.MaxConnsPerHost, tr.TLSClientConfig.InsecureSkipVerify)
+    }
+
+    client = NewHTTPClient(nil)
+    tr = client.HTTPClient.Transport.(*http.Transport)
+    if client.HTTPClient.Timeout != 30*time.Second || tr.MaxConnsPerHost != 1 {
+        t.Fatalf("timeout = %s max conns = %d, want the defaults", client.HTTPClient.Timeout, tr.MaxConnsPerHost)
+    }
+}
+
+// Ensure a pinned certificate is trusted without the CA and still recorded, and an unpinned one is rejected.
+func Test_NewHTTPClient_PinnedCertificate(t *testing.T) {
+    server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        _, _ = w.Write([]byte(`{"ok":true}`))
+    }))
+    defer server.Close()
+
+    sum := sha256.Sum256(server.Certificate().Raw)
+    for _, tc := range []struct {
+        pin     string
+        wantErr bool
+    }{
+        {pin: hex.EncodeToString(sum[:])},
+        {pin: strings.Repeat("00", sha256.Size), wantErr: true},
+    } {
+        settings := &common.TLSSettings{PinnedSHA256: []string{tc.pin}}
+        if err := settings.Load(); err != nil {
+            t.Fatalf("Load error: %v", err)
+        }
+
+        rec := &tlsRecorder{}
+        client := NewHTTPClient(withTLSRecorder(WithScrapeOptions(nil, ScrapeOptions{TLS: settings}), rec))
+        client.RetryMax = 0
+
+        u, _ := url.Parse(server.URL)
+        req, err := common.BuildRequest(server.URL+"/redfish/v1/", u.Host)
+        if err != nil {
+            t.Fatalf("BuildRequest error: %v", err)
+        }
+
+        resp, err := common.DoRequest(client, req)
+        if tc.wantErr {
+            if err == nil {
+                common.EmptyAndCloseBody(resp)
+                t.Fatalf("DoRequest with pin %s succeeded, want a pin mismatch", tc.pin)
+            }
+            continue
+        }
+        if err != nil {
+            t.Fatalf("DoRequest error: %v", err)
+        }
+        common.EmptyAndCloseBody(resp)
+
+        if leaf := rec.lastLeaf(); leaf == nil || !bytes.Equal(leaf.Raw, server.Certificate().Raw) {
+            t.Fatalf("pinned certificate was not recorded")
+        }
+    }
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression, the supported subset is the root $, the children .name and
+// ['name'], the array indexes [n] and the wildcards .* and [*]
+type jsonPath []jsonPathStep
+
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// compileJSONPath parses a JSONPath expression such as $.Status.Health or $.Temperatures[*]['@odata.id']
+func compileJSONPath(expr string) (jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q, it must start with $", expr)
+	}
+
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid JSONPath %q, empty child name", expr)
+			case "*":
+				path = append(path, jsonPathStep{wildcard: true})
+			default:
+				path = append(path, jsonPathStep{key: name})
+			}
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q, missing ]", expr)
+			}
+			sel := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case sel == "*":
+				path = append(path, jsonPathStep{wildcard: true})
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				path = append(path, jsonPathStep{key: sel[1 : len(sel)-1]})
+			default:
+				i, err := strconv.Atoi(sel)
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("invalid JSONPath %q, unsupported selector [%s]", expr, sel)
+				}
+				path = append(path, jsonPathStep{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q, unexpected %q", expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// find returns the nodes of doc matched by the path, the wildcards match the array elements in order and the
+// object values sorted by key
+func (p jsonPath) find(doc interface{}) []interface{} {
+	nodes := []interface{}{doc}
+	for _, step := range p {
+		var next []interface{}
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case map[string]interface{}:
+				if step.wildcard {
+					keys := make([]string, 0, len(n))
+					for k := range n {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, n[k])
+					}
+				} else if v, ok := n[step.key]; ok && !step.isIndex {
+					next = append(next, v)
+				}
+			case []interface{}:
+				if step.wildcard {
+					next = append(next, n...)
+				} else if step.isIndex && step.index < len(n) {
+					next = append(next, n[step.index])
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
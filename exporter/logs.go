@@ -0,0 +1,175 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/oem"
+)
+
+const (
+	// logMaxPages limits how many pages of a LogService Entries collection are read in a single scrape
+	logMaxPages = 20
+	// logStateTTL is how long the state of a log service is kept after its last scrape
+	logStateTTL = 24 * time.Hour
+)
+
+var (
+	logEntries = logStore{
+		services: make(map[string]*logState),
+	}
+)
+
+// logStore keeps the high-water mark and per severity totals of every log service across scrapes,
+// a new Exporter is created for each scrape so this state has to live outside of it
+type logStore struct {
+	mu        sync.Mutex
+	services  map[string]*logState
+	lastPrune time.Time
+}
+
+type logState struct {
+	// mark is the Created time of the newest entry seen so far
+	mark time.Time
+	// seen holds the ids of the entries created at mark, BMCs often log several entries in the same second
+	seen map[string]bool
+	// undated holds the ids of the entries without a valid Created time read in the previous scrape
+	undated  map[string]bool
+	counts   map[string]float64
+	lastSeen time.Time
+}
+
+// logServiceKey returns the key of the log service of the target host, the entries url is the one listed
+// by the LogService
+func logServiceKey(host, url string) string {
+	return host + url
+}
+
+// logEntrySeverity returns the severity of the entry, MessageSeverity replaces the deprecated Severity property
+func logEntrySeverity(entry oem.LogEntry) string {
+	if entry.MessageSeverity != "" {
+		return entry.MessageSeverity
+	}
+	if entry.Severity != "" {
+		return entry.Severity
+	}
+	return "Unknown"
+}
+
+// logEntryCreated returns the Created time of the entry, entries without a valid timestamp return the zero time
+// and are tracked by id instead of the high-water mark
+func logEntryCreated(entry oem.LogEntry) time.Time {
+	created, err := time.Parse(time.RFC3339, entry.Created)
+	if err != nil {
+		return time.Time{}
+	}
+	return created
+}
+
+// highWaterMark returns the Created time of the newest entry seen for the service
+func (s *logStore) highWaterMark(key string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.services[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	return st.mark, true
+}
+
+// observe records the entries read from the service identified by key. It returns the entries newer than the
+// high-water mark and the per severity totals. The first time a service is seen the existing entries are only
+// counted, nothing is returned as new so restarting the exporter does not replay the whole log. The entries
+// without a valid Created time are new when their id was not read in the previous scrape.
+func (s *logStore) observe(key string, entries []oem.LogEntry) ([]oem.LogEntry, map[string]float64) {
+	var newEntries []oem.LogEntry
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+
+	st, ok := s.services[key]
+	if !ok {
+		st = &logState{
+			seen:   make(map[string]bool),
+			counts: make(map[string]float64),
+		}
+		s.services[key] = st
+	}
+	st.lastSeen = now
+
+	mark := st.mark
+	undated := make(map[string]bool)
+	for _, entry := range entries {
+		created := logEntryCreated(entry)
+		if created.IsZero() {
+			undated[entry.ID] = true
+			if ok && st.undated[entry.ID] {
+				continue
+			}
+		} else if ok && (created.Before(st.mark) || (created.Equal(st.mark) && st.seen[entry.ID])) {
+			continue
+		}
+
+		st.counts[logEntrySeverity(entry)]++
+		if ok {
+			newEntries = append(newEntries, entry)
+		}
+
+		if created.After(mark) {
+			mark = created
+		}
+	}
+
+	// move the mark forward and remember the ids created at the new mark
+	if mark.After(st.mark) {
+		st.seen = make(map[string]bool)
+	}
+	for _, entry := range entries {
+		if created := logEntryCreated(entry); !created.IsZero() && created.Equal(mark) {
+			st.seen[entry.ID] = true
+		}
+	}
+	st.mark = mark
+	st.undated = undated
+
+	counts := make(map[string]float64, len(st.counts))
+	for severity, total := range st.counts {
+		counts[severity] = total
+	}
+
+	return newEntries, counts
+}
+
+// prune removes the log services that were not scraped for logStateTTL, it runs at most once an hour
+func (s *logStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < time.Hour {
+		return
+	}
+	s.lastPrune = now
+
+	for key, st := range s.services {
+		if now.Sub(st.lastSeen) > logStateTTL {
+			delete(s.services, key)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	mappingGauge   = "gauge"
+	mappingCounter = "counter"
+)
+
+var (
+	// MetricMappings holds the metric mappings of the config file
+	MetricMappings = &MetricMappingList{}
+
+	metricNameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegex  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	builtinMu       sync.Mutex
+	builtinRegistry *prometheus.Registry
+)
+
+// MetricMapping exports a metric from the fields of the Redfish resources matching a path, it adds the fields
+// no builtin component exports, such as the OEM fields of a vendor
+type MetricMapping struct {
+	// Path of the resources, a * segment matches every member of the collection, i.e.
+	// /redfish/v1/Chassis/*/Sensors/*
+	Path string `yaml:"path"`
+	Name string `yaml:"name"`
+	Help string `yaml:"help"`
+	// Type is gauge or counter, gauge when empty
+	Type string `yaml:"type,omitempty"`
+	// Value is the JSONPath of the value, a path matching several fields exports one sample for each
+	Value string `yaml:"value"`
+	// Labels are the JSONPaths of the label values keyed by label name, a path matching as many fields as the
+	// value labels the samples in order
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// ValueMap maps the values such as the Health enums to the exported value, i.e. OK: 1
+	ValueMap map[string]float64 `yaml:"valueMap,omitempty"`
+	// Vendors limits the mapping to the targets of the detected vendors
+	Vendors []string `yaml:"vendors,omitempty"`
+
+	value      jsonPath
+	labelNames []string
+	labels     []jsonPath
+}
+
+// Load validates the mapping and compiles its JSONPaths
+func (m *MetricMapping) Load() error {
+	if !metricNameRegex.MatchString(m.Name) {
+		return fmt.Errorf("invalid metric name %q", m.Name)
+	}
+	if builtinMetric(m.Name) {
+		return fmt.Errorf("metric name %s is used by a builtin metric", m.Name)
+	}
+	if m.Help == "" {
+		return fmt.Errorf("help of %s is required", m.Name)
+	}
+	if !strings.HasPrefix(m.Path, "/") {
+		return fmt.Errorf("invalid path %q of %s, it must start with /", m.Path, m.Name)
+	}
+
+	switch m.Type {
+	case "", mappingGauge, mappingCounter:
+	default:
+		return fmt.Errorf("invalid type %s of %s, valid types are: gauge, counter", m.Type, m.Name)
+	}
+
+	value, err := compileJSONPath(m.Value)
+	if err != nil {
+		return fmt.Errorf("value of %s - %s", m.Name, err.Error())
+	}
+	m.value = value
+
+	m.labelNames = m.labelNames[:0]
+	m.labels = m.labels[:0]
+	for name := range m.Labels {
+		if !labelNameRegex.MatchString(name) || strings.HasPrefix(name, "__") || name == "chassisSerialNumber" || name == "chassisModel" {
+			return fmt.Errorf("invalid label name %q of %s", name, m.Name)
+		}
+		m.labelNames = append(m.labelNames, name)
+	}
+	sort.Strings(m.labelNames)
+	for _, name := range m.labelNames {
+		label, err := compileJSONPath(m.Labels[name])
+		if err != nil {
+			return fmt.Errorf("label %s of %s - %s", name, m.Name, err.Error())
+		}
+		m.labels = append(m.labels, label)
+	}
+
+	return nil
+}
+
+// builtinMetric reports whether the metrics of the builtin components, or the telemetry metrics, use name. The
+// name is taken when the registry of the builtin metrics refuses a metric of that name
+func builtinMetric(name string) bool {
+	if strings.HasPrefix(name, "redfish_telemetry_") {
+		return true
+	}
+
+	builtinMu.Lock()
+	defer builtinMu.Unlock()
+
+	if builtinRegistry == nil {
+		builtinRegistry = prometheus.NewRegistry()
+		for _, group := range *NewDeviceMetrics() {
+			for _, gauge := range *group {
+				builtinRegistry.MustRegister(gauge)
+			}
+		}
+		for _, group := range *NewDeviceCounters() {
+			for _, counter := range *group {
+				builtinRegistry.MustRegister(counter)
+			}
+		}
+	}
+
+	gauge := newServerMetric(name, name, nil, nil)
+	if err := builtinRegistry.Register(gauge); err != nil {
+		return true
+	}
+	builtinRegistry.Unregister(gauge)
+	return false
+}
+
+// matchVendor reports whether the mapping applies to the targets of the vendor
+func (m *MetricMapping) matchVendor(vendor *Vendor) bool {
+	if len(m.Vendors) == 0 {
+		return true
+	}
+	for _, v := range m.Vendors {
+		if vendor != nil && strings.EqualFold(v, vendor.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleValue converts a field to the exported value, the strings are looked up in the value map before
+// being parsed as numbers and the booleans are 1 or 0
+func (m *MetricMapping) sampleValue(node interface{}) (float64, bool) {
+	if v, ok := m.ValueMap[labelValue(node)]; ok && node != nil {
+		return v, true
+	}
+	if b, ok := node.(bool); ok {
+		if b {
+			return 1, true
+		}
+		return 0, true
+	}
+	return parseFloat(node)
+}
+
+// labelValue returns the field as a label value, the objects and arrays are empty
+func labelValue(node interface{}) string {
+	switch v := node.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// MetricMappingList holds the metric mappings collected by the mappings component
+type MetricMappingList struct {
+	mu       sync.RWMutex
+	mappings []*MetricMapping
+}
+
+// Set replaces the metric mappings, the running scrapes finish with the mappings they started with
+func (l *MetricMappingList) Set(mappings []*MetricMapping) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mappings = mappings
+}
+
+// Get returns the metric mappings
+func (l *MetricMappingList) Get() []*MetricMapping {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.mappings
+}
+
+// mappingCollector is the collector of the mappings component, it exports the metrics of the metric mappings
+type mappingCollector struct{}
+
+// Discover expands the paths of the mappings of the target vendor, the mappings whose collections can't be
+// walked are skipped so they don't fail the scrapes of the other components
+func (c *mappingCollector) Discover(d *Discovery) ([]Resource, error) {
+	mappings := MetricMappings.Get()
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+
+	d.exp.mappings = make(map[string]*MetricMapping, len(mappings))
+	members := make(map[string][]string)
+
+	var resources []Resource
+	index := make(map[string]int)
+	for _, m := range mappings {
+		if !m.matchVendor(d.exp.vendor) {
+			continue
+		}
+		d.exp.mappings[m.Name] = m
+
+		urls, err := d.expandPath(m.Path, members)
+		if err != nil {
+			d.logError("error when expanding metric mapping path "+m.Path, err)
+			continue
+		}
+		for _, url := range urls {
+			if i, ok := index[url]; ok {
+				resources[i].Kinds = append(resources[i].Kinds, m.Name)
+				continue
+			}
+			index[url] = len(resources)
+			resources = append(resources, Resource{URL: url, Kinds: []string{m.Name}})
+		}
+	}
+
+	return resources, nil
+}
+
+// expandPath returns the resources matching path, the walked collections are kept in members
+func (d *Discovery) expandPath(path string, members map[string][]string) ([]string, error) {
+	prefixes := []string{""}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		var next []string
+		for _, prefix := range prefixes {
+			if segment != "*" {
+				next = append(next, prefix+"/"+segment)
+				continue
+			}
+
+			coll := prefix + "/"
+			urls, ok := members[coll]
+			if !ok {
+				var err error
+				urls, err = d.Members(coll)
+				if err != nil {
+					return nil, err
+				}
+				members[coll] = urls
+			}
+			for _, url := range urls {
+				next = append(next, strings.TrimSuffix(url, "/"))
+			}
+		}
+		prefixes = next
+	}
+
+	urls := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		urls = append(urls, appendSlash(prefix))
+	}
+	return urls, nil
+}
+
+func (c *mappingCollector) Describe() map[string]*prometheus.GaugeVec {
+	return nil
+}
+
+// Parse exports the samples of the mapping named kind, the metrics are created on first use since the mappings
+// change on reload
+func (c *mappingCollector) Parse(e *Exporter, kind string, body []byte) error {
+	m, ok := e.mappings[kind]
+	if !ok {
+		return fmt.Errorf("unknown metric mapping %s", kind)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("error unmarshalling metric mapping %s - %s", m.Name, err.Error())
+	}
+
+	values := m.value.find(doc)
+	labels := make([][]interface{}, len(m.labels))
+	for i, label := range m.labels {
+		labels[i] = label.find(doc)
+		if n := len(labels[i]); n > 1 && n != len(values) {
+			return fmt.Errorf("label %s of metric mapping %s matched %d fields for %d values", m.labelNames[i], m.Name, n, len(values))
+		}
+	}
+
+	for i, node := range values {
+		value, ok := m.sampleValue(node)
+		if !ok {
+			continue
+		}
+
+		labelValues := make([]string, 0, len(labels)+2)
+		for _, l := range labels {
+			switch len(l) {
+			case 0:
+				labelValues = append(labelValues, "")
+			case 1:
+				labelValues = append(labelValues, labelValue(l[0]))
+			default:
+				labelValues = append(labelValues, labelValue(l[i]))
+			}
+		}
+		labelValues = append(labelValues, e.ChassisSerialNumber, e.Model)
+
+		if m.Type == mappingCounter {
+			// the counters mirror a total kept by the BMC, a negative reading is not a valid total
+			if value < 0 {
+				continue
+			}
+			e.mappingCounter(m).WithLabelValues(labelValues...).Add(value)
+		} else {
+			e.mappingGauge(m).WithLabelValues(labelValues...).Set(value)
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) mappingGauge(m *MetricMapping) *prometheus.GaugeVec {
+	group, ok := (*e.DeviceMetrics)[string(ComponentMappings)]
+	if !ok {
+		group = &metrics{}
+		(*e.DeviceMetrics)[string(ComponentMappings)] = group
+	}
+	gauge, ok := (*group)[m.Name]
+	if !ok {
+		gauge = newServerMetric(m.Name, m.Help, nil, append(append([]string{}, m.labelNames...), "chassisSerialNumber", "chassisModel"))
+		(*group)[m.Name] = gauge
+	}
+	return gauge
+}
+
+func (e *Exporter) mappingCounter(m *MetricMapping) *prometheus.CounterVec {
+	group, ok := (*e.DeviceCounters)[string(ComponentMappings)]
+	if !ok {
+		group = &counterMetrics{}
+		(*e.DeviceCounters)[string(ComponentMappings)] = group
+	}
+	counter, ok := (*group)[m.Name]
+	if !ok {
+		counter = newServerCounter(m.Name, m.Help, nil, append(append([]string{}, m.labelNames...), "chassisSerialNumber", "chassisModel"))
+		(*group)[m.Name] = counter
+	}
+	return counter
+}
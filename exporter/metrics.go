@@ -22,6 +22,8 @@ import (
 
 type metrics map[string]*prometheus.GaugeVec
 
+type counterMetrics map[string]*prometheus.CounterVec
+
 func newServerMetric(metricName string, docString string, constLabels prometheus.Labels, labelNames []string) *prometheus.GaugeVec {
 	return prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -33,6 +35,17 @@ func newServerMetric(metricName string, docString string, constLabels prometheus
 	)
 }
 
+func newServerCounter(metricName string, docString string, constLabels prometheus.Labels, labelNames []string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        metricName,
+			Help:        docString,
+			ConstLabels: constLabels,
+		},
+		labelNames,
+	)
+}
+
 func NewDeviceMetrics() *map[string]*metrics {
 	var (
 		UpMetric = &metrics{
@@ -49,13 +62,25 @@ func NewDeviceMetrics() *map[string]*metrics {
 		}
 
 		PowerMetrics = &metrics{
-			"voltageOutput":       newServerMetric("redfish_power_voltage_output", "Power voltage output in volts", nil, []string{"name", "chassisSerialNumber", "chassisModel"}),
-			"voltageStatus":       newServerMetric("redfish_power_voltage_status", "Current power voltage status 1 = OK, 0 = BAD", nil, []string{"name", "chassisSerialNumber", "chassisModel"}),
-			"lineInputVoltage":    newServerMetric("redfish_power_supply_input_volts", "The line input voltage at which the Power Supply is operating in volts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
-			"supplyInput":         newServerMetric("redfish_power_supply_input_watts", "The power input of Power Supply in watts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
-			"supplyOutput":        newServerMetric("redfish_power_supply_output", "The average power output of Power Supply in watts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
-			"supplyStatus":        newServerMetric("redfish_power_supply_status", "Current power supply status 1 = OK, 0 = BAD", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
-			"supplyTotalConsumed": newServerMetric("redfish_power_supply_total_consumed", "Total output of all power supplies in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"voltageOutput":         newServerMetric("redfish_power_voltage_output", "Power voltage output in volts", nil, []string{"name", "chassisSerialNumber", "chassisModel"}),
+			"voltageStatus":         newServerMetric("redfish_power_voltage_status", "Current power voltage status 1 = OK, 0 = BAD", nil, []string{"name", "chassisSerialNumber", "chassisModel"}),
+			"lineInputVoltage":      newServerMetric("redfish_power_supply_input_volts", "The line input voltage at which the Power Supply is operating in volts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
+			"supplyInput":           newServerMetric("redfish_power_supply_input_watts", "The power input of Power Supply in watts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
+			"supplyOutput":          newServerMetric("redfish_power_supply_output", "The average power output of Power Supply in watts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
+			"supplyStatus":          newServerMetric("redfish_power_supply_status", "Current power supply status 1 = OK, 0 = BAD", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
+			"supplyTotalConsumed":   newServerMetric("redfish_power_supply_total_consumed", "Total output of all power supplies in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"supplyCapacity":        newServerMetric("redfish_power_supply_capacity_watts", "The maximum capacity of the Power Supply in watts", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
+			"supplyEfficiency":      newServerMetric("redfish_power_supply_efficiency_percent", "The rated efficiency of the Power Supply in percent", nil, []string{"name", "chassisSerialNumber", "chassisModel", "manufacturer", "serialNumber", "firmwareVersion", "powerSupplyType", "bayNumber", "model"}),
+			"controlCapacity":       newServerMetric("redfish_power_control_capacity_watts", "Total power capacity available for allocation in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"controlLimit":          newServerMetric("redfish_power_control_limit_watts", "Configured power cap in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"controlLimitException": newServerMetric("redfish_power_control_limit_exception", "Action taken when the power cap is exceeded", nil, []string{"memberId", "chassisSerialNumber", "chassisModel", "limitException"}),
+			"controlMinConsumed":    newServerMetric("redfish_power_control_min_consumed_watts", "Lowest power consumption over the BMC measurement interval in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"controlAvgConsumed":    newServerMetric("redfish_power_control_avg_consumed_watts", "Average power consumption over the BMC measurement interval in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"controlMaxConsumed":    newServerMetric("redfish_power_control_max_consumed_watts", "Highest power consumption over the BMC measurement interval in watts", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"controlInterval":       newServerMetric("redfish_power_control_interval_minutes", "BMC measurement interval for the min/avg/max consumption metrics in minutes", nil, []string{"memberId", "chassisSerialNumber", "chassisModel"}),
+			"subsystemCapacity":     newServerMetric("redfish_power_subsystem_capacity_watts", "Total power capacity of the PowerSubsystem in watts", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"subsystemAllocated":    newServerMetric("redfish_power_subsystem_allocated_watts", "Total power allocated to the PowerSubsystem in watts", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"subsystemRequested":    newServerMetric("redfish_power_subsystem_requested_watts", "Total power requested from the PowerSubsystem in watts", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
 		}
 
 		ProcessorMetrics = &metrics{
@@ -100,6 +125,58 @@ func NewDeviceMetrics() *map[string]*metrics {
 			"componentFirmware": newServerMetric("redfish_component_firmware", "Current firmware component status 1 = OK, 0 = BAD", nil, []string{"id", "name", "description", "version"}),
 		}
 
+		ManagerMetrics = &metrics{
+			"managerStatus":          newServerMetric("redfish_manager_status", "Current manager status 1 = OK, 0 = BAD, -1 = DISABLED", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerClockOffset":     newServerMetric("redfish_manager_clock_offset_seconds", "Difference between the manager DateTime and the exporter clock in seconds, positive when the manager is ahead", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerLastReset":       newServerMetric("redfish_manager_last_reset_timestamp_seconds", "Time the manager was last reset as a unix timestamp", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerUptime":          newServerMetric("redfish_manager_uptime_seconds", "Time since the manager was last reset in seconds", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerMemoryTotal":     newServerMetric("redfish_manager_memory_total_bytes", "Total memory of the manager in bytes", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerMemoryUsed":      newServerMetric("redfish_manager_memory_used_bytes", "Used memory of the manager in bytes", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerMemoryAvailable": newServerMetric("redfish_manager_memory_available_bytes", "Available memory of the manager in bytes", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerCPUKernel":       newServerMetric("redfish_manager_cpu_kernel_percent", "Percentage of manager CPU time spent in kernel mode", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerCPUUser":         newServerMetric("redfish_manager_cpu_user_percent", "Percentage of manager CPU time spent in user mode", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerFreeStorage":     newServerMetric("redfish_manager_free_storage_bytes", "Free storage space of the manager in bytes", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+			"managerServiceUptime":   newServerMetric("redfish_manager_service_uptime_seconds", "Time since the manager Redfish service was started in seconds", nil, []string{"url", "chassisSerialNumber", "chassisModel"}),
+		}
+
+		SecurityMetrics = &metrics{
+			"protocolEnabled":         newServerMetric("redfish_security_protocol_enabled", "Current manager network protocol state 1 = enabled, 0 = disabled", nil, []string{"protocol", "port", "chassisSerialNumber", "chassisModel"}),
+			"accountLockoutThreshold": newServerMetric("redfish_security_account_lockout_threshold", "Number of failed logins before an account is locked, 0 = lockout disabled", nil, []string{"chassisSerialNumber", "chassisModel"}),
+			"accountLockoutDuration":  newServerMetric("redfish_security_account_lockout_duration_seconds", "Time an account stays locked after reaching the lockout threshold in seconds", nil, []string{"chassisSerialNumber", "chassisModel"}),
+			"minPasswordLength":       newServerMetric("redfish_security_min_password_length", "Minimum password length required for accounts", nil, []string{"chassisSerialNumber", "chassisModel"}),
+			"accountsEnabled":         newServerMetric("redfish_security_accounts_enabled", "Number of enabled BMC accounts", nil, []string{"chassisSerialNumber", "chassisModel"}),
+			"defaultAccountEnabled":   newServerMetric("redfish_security_default_account_enabled", "Is a factory default BMC account enabled 1 = enabled, 0 = not enabled", nil, []string{"chassisSerialNumber", "chassisModel"}),
+			"secureBootEnabled":       newServerMetric("redfish_security_secure_boot_enabled", "Current UEFI Secure Boot state 1 = enabled, 0 = disabled", nil, []string{"mode", "chassisSerialNumber", "chassisModel"}),
+			"trustedModuleEnabled":    newServerMetric("redfish_security_trusted_module_enabled", "Current trusted module state 1 = enabled, 0 = disabled", nil, []string{"interfaceType", "firmwareVersion", "chassisSerialNumber", "chassisModel"}),
+		}
+
+		CertificateMetrics = &metrics{
+			"certificateNotAfter":    newServerMetric("redfish_certificate_not_after_seconds", "Certificate expiry time as a unix timestamp, usage Served is the certificate presented during the TLS handshake", nil, []string{"subject", "issuer", "usage", "chassisSerialNumber", "chassisModel"}),
+			"certificateServedMatch": newServerMetric("redfish_certificate_served_match", "Does the served TLS certificate match one of the manager HTTPS certificates 1 = match, 0 = mismatch", nil, []string{"chassisSerialNumber", "chassisModel"}),
+		}
+
+		SystemMetrics = &metrics{
+			"powerState":      newServerMetric("redfish_system_power_state", "Current host power state 1 = On, 0 = Off or transitioning", nil, []string{"chassisSerialNumber", "chassisModel", "powerState"}),
+			"bootProgress":    newServerMetric("redfish_system_boot_progress", "Last host boot progress state 1 = OSRunning, 0 = any other state", nil, []string{"chassisSerialNumber", "chassisModel", "lastState"}),
+			"postState":       newServerMetric("redfish_system_post_state", "Current HPE POST state 1 = FinishedPost, 0 = any other state", nil, []string{"chassisSerialNumber", "chassisModel", "postState"}),
+			"indicatorActive": newServerMetric("redfish_system_indicator_active", "Current location indicator state 1 = Lit or Blinking, 0 = Off", nil, []string{"chassisSerialNumber", "chassisModel", "indicatorLED"}),
+			"healthRollup":    newServerMetric("redfish_system_health_rollup", "Current system health rollup 1 = OK, 0 = BAD", nil, []string{"chassisSerialNumber", "chassisModel", "healthRollup"}),
+		}
+
+		RedundancyMetrics = &metrics{
+			"redundancyStatus":         newServerMetric("redfish_redundancy_status", "Current redundancy group status 1 = OK, 0 = BAD, -1 = DISABLED", nil, []string{"url", "group", "mode", "chassisSerialNumber", "chassisModel"}),
+			"redundancyMinMembers":     newServerMetric("redfish_redundancy_min_members", "Minimum number of members needed for the redundancy group to be redundant", nil, []string{"url", "group", "mode", "chassisSerialNumber", "chassisModel"}),
+			"redundancyMaxMembers":     newServerMetric("redfish_redundancy_max_members", "Maximum number of members supported in the redundancy group", nil, []string{"url", "group", "mode", "chassisSerialNumber", "chassisModel"}),
+			"redundancyHealthyMembers": newServerMetric("redfish_redundancy_healthy_members", "Current number of healthy members in the redundancy group", nil, []string{"url", "group", "mode", "chassisSerialNumber", "chassisModel"}),
+		}
+
+		// TelemetryMetrics are created while scraping, one gauge for each MetricId found in the metric reports
+		TelemetryMetrics = &metrics{}
+
+		RefreshMetrics = &metrics{
+			"componentLastRefresh": newServerMetric("redfish_component_last_refresh_timestamp_seconds", "Unix time the resources of the component were last fetched from the BMC, they are served from the last successful result until the refresh interval has passed", nil, []string{"component", "chassisSerialNumber", "chassisModel"}),
+		}
+
 		DeviceMetrics = &metrics{
 			"deviceInfo": newServerMetric("redfish_device_info", "Current snapshot of device firmware information", nil, []string{"name", "chassisSerialNumber", "chassisModel", "firmwareVersion", "biosVersion"}),
 		}
@@ -117,9 +194,39 @@ func NewDeviceMetrics() *map[string]*metrics {
 			"iloSelfTestMetrics":       IloSelfTestMetrics,
 			"firmwareInventoryMetrics": FirmwareInventoryMetrics,
 			"memoryMetrics":            MemoryMetrics,
+			"managerMetrics":           ManagerMetrics,
+			"systemMetrics":            SystemMetrics,
+			"securityMetrics":          SecurityMetrics,
+			"certificateMetrics":       CertificateMetrics,
+			"redundancyMetrics":        RedundancyMetrics,
+			"telemetryMetrics":         TelemetryMetrics,
+			"refreshMetrics":           RefreshMetrics,
 			"deviceInfo":               DeviceMetrics,
 		}
 	)
 
+	describeCollectors(*Metrics)
+
 	return Metrics
 }
+
+// NewDeviceCounters returns the counter metrics, these hold values that are accumulated
+// across scrapes and are set once per scrape from the stored totals
+func NewDeviceCounters() *map[string]*counterMetrics {
+	var (
+		EnergyMetrics = &counterMetrics{
+			"energyTotal": newServerCounter("redfish_energy_joules_total", "Total energy consumed in joules, accumulated across BMC sensor resets", nil, []string{"url", "sensor", "chassisSerialNumber", "chassisModel"}),
+		}
+
+		LogMetrics = &counterMetrics{
+			"logEntriesTotal": newServerCounter("redfish_log_entries_total", "Total number of log service entries by severity, counted from when the exporter first read the service", nil, []string{"service", "severity", "chassisSerialNumber", "chassisModel"}),
+		}
+
+		Counters = &map[string]*counterMetrics{
+			"energyMetrics": EnergyMetrics,
+			"logMetrics":    LogMetrics,
+		}
+	)
+
+	return Counters
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/comcast/fishymetrics/common"
+)
+
+type scrapeOptionsCtxKey string
+
+const scrapeOptionsKey scrapeOptionsCtxKey = "scrape-options"
+
+// ScrapeOptions overrides the global client settings for one scrape, the zero value keeps them all
+type ScrapeOptions struct {
+	// Timeout of each request sent to the BMC, 30s when 0
+	Timeout time.Duration
+	// Concurrency is the number of requests sent to the BMC at once, 1 when 0
+	Concurrency int
+	// TLS overrides the --insecure-skip-verify flag when set
+	TLS *common.TLSSettings
+	// SessionAuth authenticates with a Redfish session token instead of basic auth
+	SessionAuth bool
+}
+
+// WithScrapeOptions returns a new context that carries the client settings of a scrape.
+func WithScrapeOptions(ctx context.Context, opts ScrapeOptions) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, scrapeOptionsKey, opts)
+}
+
+// WithTargetTLS returns a new context that carries the TLS settings of the target patterns matching target,
+// the TLS settings already carried by ctx take precedence.
+func WithTargetTLS(ctx context.Context, target string) context.Context {
+	opts := scrapeOptionsFromContext(ctx)
+	if opts.TLS != nil {
+		return ctx
+	}
+	opts.TLS = common.TargetTLS.Get(target)
+	return WithScrapeOptions(ctx, opts)
+}
+
+func scrapeOptionsFromContext(ctx context.Context) ScrapeOptions {
+	if ctx == nil {
+		return ScrapeOptions{}
+	}
+	opts, _ := ctx.Value(scrapeOptionsKey).(ScrapeOptions)
+	return opts
+}
+
+func (o ScrapeOptions) concurrency() int {
+	if o.Concurrency > 0 {
+		return o.Concurrency
+	}
+	return 1
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"go.uber.org/zap"
+)
+
+var (
+	// registeredPlugins are the plugins of RegisterPlugin keyed by name
+	registeredPlugins = map[string]PluginInfo{}
+)
+
+// PluginInfo describes a plugin picked by name with the plugins query parameter
+type PluginInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Models are the model parameters or the detected vendors the plugin was written for, all of them when empty.
+	// The plugin still runs for the other targets, with a warning
+	Models []string `json:"models,omitempty"`
+	// New returns the plugin applied to one scrape
+	New func() Plugin `json:"-"`
+}
+
+// RegisterPlugin adds a plugin to the plugins picked by name. It must be called from an init function and panics
+// when the name is already registered
+func RegisterPlugin(info PluginInfo) {
+	if info.Name == "" || info.New == nil {
+		panic("plugin name and constructor are required")
+	}
+	if _, ok := registeredPlugins[info.Name]; ok {
+		panic(fmt.Sprintf("plugin %s already registered", info.Name))
+	}
+	registeredPlugins[info.Name] = info
+}
+
+// Plugins returns the registered plugins sorted by name
+func Plugins() []PluginInfo {
+	infos := make([]PluginInfo, 0, len(registeredPlugins))
+	for _, info := range registeredPlugins {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// PluginNames returns the names of the registered plugins sorted by name
+func PluginNames() []string {
+	names := make([]string, 0, len(registeredPlugins))
+	for _, info := range Plugins() {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+// ParsePlugins parses a comma-separated list of plugin names and returns a new instance of each, an unknown
+// name is an error
+func ParsePlugins(pluginsStr string) ([]Plugin, error) {
+	var result []Plugin
+	seen := make(map[string]bool)
+
+	for _, p := range strings.Split(pluginsStr, ",") {
+		name := strings.TrimSpace(p)
+		if name == "" || seen[name] {
+			continue
+		}
+		info, ok := registeredPlugins[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown plugin %s. Valid plugins are: %s", name, strings.Join(PluginNames(), ", "))
+		}
+		seen[name] = true
+		result = append(result, &registeredPlugin{Plugin: info.New(), info: info})
+	}
+
+	return result, nil
+}
+
+// supports reports whether the plugin was written for the model parameter or the detected vendor
+func (info PluginInfo) supports(model string, vendor *Vendor) bool {
+	if len(info.Models) == 0 {
+		return true
+	}
+	for _, m := range info.Models {
+		if strings.EqualFold(m, model) || (vendor != nil && strings.EqualFold(m, vendor.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// registeredPlugin is a plugin of ParsePlugins, it warns when the target isn't one of its models
+type registeredPlugin struct {
+	Plugin
+	info PluginInfo
+}
+
+func (p *registeredPlugin) Apply(e *Exporter) error {
+	if !p.info.supports(e.Model, e.vendor) {
+		log.Warn("plugin "+p.info.Name+" was not written for the target model", zap.String("model", e.Model),
+			zap.Strings("plugin_models", p.info.Models), zap.Any("trace_id", e.ctx.Value(logging.TraceIDKey("traceID"))))
+	}
+	return p.Plugin.Apply(e)
+}
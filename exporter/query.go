@@ -0,0 +1,226 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+var (
+	// selectProperties are the properties each member handler reads, they are requested with $select
+	// when the BMC supports it
+	selectProperties = map[string][]string{
+		MEMORY:             jsonProperties(oem.MemoryMetrics{}),
+		PROCESSOR:          jsonProperties(oem.ProcessorMetrics{}),
+		STORAGE_CONTROLLER: jsonProperties(oem.StorageControllerMetrics{}),
+		LOGICALDRIVE:       jsonProperties(oem.LogicalDriveMetrics{}),
+		UNKNOWN_DRIVE:      jsonProperties(oem.DriveProtocol{}, oem.NVMeDriveMetrics{}, oem.DiskDriveMetrics{}),
+	}
+
+	// revalidatedKinds are the slow-changing members whose responses are kept and revalidated with their ETag
+	revalidatedKinds = map[string]bool{
+		MEMORY:             true,
+		PROCESSOR:          true,
+		STORAGE_CONTROLLER: true,
+	}
+)
+
+// queryOptions uses the $expand and $select query parameters advertised in the ServiceRoot ProtocolFeaturesSupported.
+// Collections are fetched with their members expanded and the members are kept so the member tasks don't have to
+// request them again, members that still have to be fetched only request the properties their handlers need.
+type queryOptions struct {
+	mu          sync.Mutex
+	expand      string
+	selectQuery bool
+	skipQuery   bool
+	members     map[string][]byte
+}
+
+// newQueryOptions returns the query options supported by the service root, a nil queryOptions fetches everything as is
+func newQueryOptions(features oem.ProtocolFeaturesSupported) *queryOptions {
+	q := &queryOptions{
+		selectQuery: features.SelectQuery,
+		skipQuery:   features.TopSkipQuery,
+		members:     make(map[string][]byte),
+	}
+
+	// $expand=. expands the members without following the Links section
+	if features.ExpandQuery.NoLinks {
+		q.expand = "$expand=."
+		if features.ExpandQuery.Levels {
+			q.expand = "$expand=.($levels=1)"
+		}
+	}
+
+	return q
+}
+
+// skip reports whether the BMC supports the $skip query parameter to page collections from an offset
+func (q *queryOptions) skip() bool {
+	return q != nil && q.skipQuery
+}
+
+// collection returns a fetch function for the collection at uri that expands its members when supported,
+// it falls back to a regular request and stops expanding if the BMC rejects the query
+func (q *queryOptions) collection(uri, host, profile string, client *retryablehttp.Client) func() ([]byte, error) {
+	if q == nil {
+		return common.Fetch(uri, host, profile, client)
+	}
+
+	return func() ([]byte, error) {
+		if body, ok := q.cached(uri); ok {
+			return body, nil
+		}
+
+		q.mu.Lock()
+		expand := q.expand
+		q.mu.Unlock()
+
+		if expand != "" {
+			body, err := common.Fetch(withQuery(uri, expand), host, profile, client)()
+			if err == nil {
+				q.keepMembers(body)
+				return body, nil
+			}
+			if err == common.ErrInvalidCredential {
+				return nil, err
+			}
+
+			q.mu.Lock()
+			q.expand = ""
+			q.mu.Unlock()
+		}
+
+		return common.Fetch(uri, host, profile, client)()
+	}
+}
+
+// member returns a fetch function for the member at uri, expanded members are returned without a request
+// and the others only request the properties used by the handlers of metricType
+func (q *queryOptions) member(uri, host, profile string, client *retryablehttp.Client, metricType string) func() ([]byte, error) {
+	fetch := common.Fetch
+	if revalidatedKinds[metricType] {
+		fetch = common.FetchRevalidated
+	}
+
+	if q == nil {
+		return fetch(uri, host, profile, client)
+	}
+
+	return func() ([]byte, error) {
+		if body, ok := q.cached(uri); ok {
+			return body, nil
+		}
+
+		q.mu.Lock()
+		selectQuery := q.selectQuery
+		q.mu.Unlock()
+
+		if props := selectProperties[metricType]; selectQuery && len(props) > 0 {
+			body, err := fetch(withQuery(uri, "$select="+strings.Join(props, ",")), host, profile, client)()
+			if err == nil {
+				return body, nil
+			}
+			if err == common.ErrInvalidCredential {
+				return nil, err
+			}
+
+			q.mu.Lock()
+			q.selectQuery = false
+			q.mu.Unlock()
+		}
+
+		return fetch(uri, host, profile, client)()
+	}
+}
+
+func (q *queryOptions) cached(uri string) ([]byte, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	body, ok := q.members[memberKey(uri)]
+	return body, ok
+}
+
+// keepMembers stores the expanded members of a collection, members that only contain their @odata.id
+// were not expanded by the BMC
+func (q *queryOptions) keepMembers(body []byte) {
+	var coll struct {
+		Members []json.RawMessage `json:"Members"`
+	}
+
+	if err := json.Unmarshal(body, &coll); err != nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, raw := range coll.Members {
+		var member map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &member); err != nil || len(member) < 2 {
+			continue
+		}
+
+		var id string
+		if err := json.Unmarshal(member["@odata.id"], &id); err != nil || id == "" {
+			continue
+		}
+		q.members[memberKey(id)] = raw
+	}
+}
+
+// memberKey returns the path of uri without a trailing slash so urls and @odata.id values match
+func memberKey(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		uri = u.Path
+	}
+	return strings.TrimSuffix(uri, "/")
+}
+
+func withQuery(uri, query string) string {
+	if strings.Contains(uri, "?") {
+		return uri + "&" + query
+	}
+	return uri + "?" + query
+}
+
+// jsonProperties returns the top level json property names of the structs provided
+func jsonProperties(structs ...interface{}) []string {
+	var props []string
+
+	for _, s := range structs {
+		t := reflect.TypeOf(s)
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" || strings.HasPrefix(name, "@") || !checkUnique(props, name) {
+				continue
+			}
+			props = append(props, name)
+		}
+	}
+
+	return props
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/comcast/fishymetrics/oem"
+)
+
+// redundancyGroup is a PSU or fan redundancy group collected while handling the Power, Thermal,
+// PowerSubsystem and ThermalSubsystem responses. The healthy member count can only be worked out
+// once every member has been handled, so the metrics are set in exportRedundancyMetrics.
+type redundancyGroup struct {
+	url          string
+	group        string
+	mode         string
+	status       oem.Status
+	minNeeded    interface{}
+	maxSupported interface{}
+	members      []string
+}
+
+// redundancyMemberID normalizes the member urls so that both "/Power#/PowerSupplies/0" and
+// "/Power/#PowerSupplies/0" style references match the url of the member itself
+func redundancyMemberID(url string) string {
+	url = strings.Replace(url, "/#", "#", 1)
+	url = strings.Replace(url, "#/", "#", 1)
+	return strings.TrimSuffix(url, "/")
+}
+
+// recordMemberHealth stores the health of a power supply or fan so it can be matched against redundancy group members
+func (e *Exporter) recordMemberHealth(url string, healthy bool) {
+	if url == "" {
+		return
+	}
+	if e.memberHealth == nil {
+		e.memberHealth = make(map[string]bool)
+	}
+	e.memberHealth[redundancyMemberID(url)] = healthy
+}
+
+// recordRedundancy stores the legacy Redundancy groups found in the Power and Thermal resources
+func (e *Exporter) recordRedundancy(url string, groups []oem.Redundancy) {
+	for _, r := range groups {
+		group := r.Name
+		if group == "" && r.MemberID != nil {
+			group = fmt.Sprint(r.MemberID)
+		}
+
+		var members []string
+		for _, m := range r.RedundancySet {
+			members = append(members, m.URL)
+		}
+
+		e.redundancyGroups = append(e.redundancyGroups, redundancyGroup{
+			url:          url,
+			group:        group,
+			mode:         r.Mode,
+			status:       r.Status,
+			minNeeded:    r.MinNumNeeded,
+			maxSupported: r.MaxNumSupported,
+			members:      members,
+		})
+	}
+}
+
+// recordRedundantGroups stores the redundancy groups found in the PowerSubsystem and ThermalSubsystem resources,
+// these groups have no name so the property name and index are used instead
+func (e *Exporter) recordRedundantGroups(url string, property string, groups []oem.RedundantGroup) {
+	for i, r := range groups {
+		var members []string
+		for _, m := range r.RedundancyGroup {
+			members = append(members, m.URL)
+		}
+
+		e.redundancyGroups = append(e.redundancyGroups, redundancyGroup{
+			url:          url,
+			group:        fmt.Sprintf("%s/%d", property, i),
+			mode:         r.RedundancyType,
+			status:       r.Status,
+			minNeeded:    r.MinNeededInGroup,
+			maxSupported: r.MaxSupportedInGroup,
+			members:      members,
+		})
+	}
+}
+
+// exportRedundancyMetrics sets the prometheus gauges for the redundancy groups once all of the tasks have been handled
+func (e *Exporter) exportRedundancyMetrics() {
+	var state float64
+	var red = (*e.DeviceMetrics)["redundancyMetrics"]
+
+	for _, g := range e.redundancyGroups {
+		if g.status.State == "Enabled" || g.status.State == "" {
+			if g.status.Health == "OK" {
+				state = OK
+			} else {
+				state = BAD
+			}
+		} else {
+			state = DISABLED
+		}
+
+		(*red)["redundancyStatus"].WithLabelValues(g.url, g.group, g.mode, e.ChassisSerialNumber, e.Model).Set(state)
+
+		if minNeeded, ok := parseFloat(g.minNeeded); ok {
+			(*red)["redundancyMinMembers"].WithLabelValues(g.url, g.group, g.mode, e.ChassisSerialNumber, e.Model).Set(minNeeded)
+		}
+		if maxSupported, ok := parseFloat(g.maxSupported); ok {
+			(*red)["redundancyMaxMembers"].WithLabelValues(g.url, g.group, g.mode, e.ChassisSerialNumber, e.Model).Set(maxSupported)
+		}
+
+		if len(g.members) > 0 {
+			var healthy float64
+			for _, m := range g.members {
+				if e.memberHealth[redundancyMemberID(m)] {
+					healthy++
+				}
+			}
+			(*red)["redundancyHealthyMembers"].WithLabelValues(g.url, g.group, g.mode, e.ChassisSerialNumber, e.Model).Set(healthy)
+		}
+	}
+
+	e.redundancyGroups = nil
+}
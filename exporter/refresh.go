@@ -0,0 +1,134 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/config"
+)
+
+const (
+	// refreshTargetTTL is how long the refresh times of a target that is no longer scraped are kept
+	refreshTargetTTL = 24 * time.Hour
+)
+
+var (
+	componentRefreshes = refreshStore{
+		targets: make(map[string]*refreshTarget),
+	}
+)
+
+// refreshStore keeps when the resources of each component were last fetched from the BMC per target,
+// a new Exporter is created for each scrape so this state has to live outside of it
+type refreshStore struct {
+	mu        sync.Mutex
+	targets   map[string]*refreshTarget
+	lastPrune time.Time
+}
+
+type refreshTarget struct {
+	components map[ComponentType]time.Time
+	lastSeen   time.Time
+}
+
+func (s *refreshStore) mark(host string, component ComponentType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+
+	t, ok := s.targets[host]
+	if !ok {
+		t = &refreshTarget{components: make(map[ComponentType]time.Time)}
+		s.targets[host] = t
+	}
+	t.components[component] = now
+	t.lastSeen = now
+}
+
+func (s *refreshStore) get(host string, component ComponentType) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.targets[host]
+	if !ok {
+		return time.Time{}, false
+	}
+	// the components served from the last result are still scraped
+	t.lastSeen = time.Now()
+	refreshed, ok := t.components[component]
+	return refreshed, ok
+}
+
+// prune drops the targets not scraped for refreshTargetTTL, it walks the targets at most once an hour
+func (s *refreshStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < time.Hour {
+		return
+	}
+	s.lastPrune = now
+
+	for host, t := range s.targets {
+		if now.Sub(t.lastSeen) > refreshTargetTTL {
+			delete(s.targets, host)
+		}
+	}
+}
+
+// withRefresh serves the last successful response of uri until the refresh interval configured for component
+// has passed, the time the component was last fetched from the BMC is exported after the scrape
+func (e *Exporter) withRefresh(component ComponentType, uri string, fetch func() ([]byte, error)) func() ([]byte, error) {
+	if e.refreshed == nil {
+		e.refreshed = make(map[ComponentType]bool)
+	}
+	e.refreshed[component] = true
+
+	host := e.host
+	refresh := func() ([]byte, error) {
+		body, err := fetch()
+		if err == nil {
+			componentRefreshes.mark(host, component)
+		}
+		return body, err
+	}
+
+	return common.Responses.WithMaxAge(refresh, uri, host, refreshInterval(component))
+}
+
+// refreshInterval returns the refresh interval of component, the deprecated cache max-age of the component
+// applies when it has no refresh interval
+func refreshInterval(component ComponentType) time.Duration {
+	cfg := config.GetConfig()
+	if d, ok := cfg.RefreshIntervals[string(component)]; ok {
+		return d
+	}
+	return cfg.CacheMaxAge[string(component)]
+}
+
+// exportRefreshMetrics sets the last refresh time of the components collected by this scrape
+func (e *Exporter) exportRefreshMetrics() {
+	var refresh = (*e.DeviceMetrics)["refreshMetrics"]
+
+	for component := range e.refreshed {
+		if t, ok := componentRefreshes.get(e.host, component); ok {
+			(*refresh)["componentLastRefresh"].WithLabelValues(string(component), e.ChassisSerialNumber, e.Model).Set(float64(t.Unix()))
+		}
+	}
+}
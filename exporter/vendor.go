@@ -0,0 +1,233 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/oem"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+var (
+	vendorsMu sync.RWMutex
+	// vendors are matched in registration order, the builtin vendors first
+	vendors = []*Vendor{
+		{
+			Name:        "hpe",
+			OemKeys:     []string{"Hpe", "Hp"},
+			Identifiers: []string{"hpe", "hewlett", "proliant", "ilo"},
+		},
+		{
+			Name:        "dell",
+			OemKeys:     []string{"Dell"},
+			Identifiers: []string{"dell", "idrac"},
+			Quirks: common.Quirks{
+				// this header is required by iDRAC9 with FW ver. 3.xx and 4.xx
+				Accept: "application/json",
+			},
+		},
+		{
+			Name:        "cisco",
+			OemKeys:     []string{"Cisco"},
+			Identifiers: []string{"cisco", "cimc", "ucs"},
+			// scrape the other manager when the BMC also lists its CIMC
+			SkipManagers: []string{"CIMC"},
+			Quirks: common.Quirks{
+				// cisco devices respond in a non idiomatic manner to back to back requests
+				RequestDelay: 100 * time.Millisecond,
+			},
+		},
+		{
+			Name:        "lenovo",
+			OemKeys:     []string{"Lenovo"},
+			Identifiers: []string{"lenovo", "xclarity"},
+		},
+		{
+			Name:        "supermicro",
+			OemKeys:     []string{"Supermicro"},
+			Identifiers: []string{"supermicro"},
+		},
+	}
+
+	// DefaultVendor is used for the BMCs whose vendor isn't detected, it keeps the quirks of all the vendors
+	DefaultVendor = &Vendor{
+		Name:         "unknown",
+		SkipManagers: []string{"CIMC"},
+		Quirks:       common.DefaultQuirks,
+	}
+
+	detectedVendors = &vendorCache{
+		vendors: make(map[string]*Vendor),
+	}
+)
+
+// Vendor holds the quirks of the BMCs of one vendor
+type Vendor struct {
+	Name string
+	// OemKeys are the namespaces of the service root Oem object that identify the vendor
+	OemKeys []string
+	// Identifiers are matched case-insensitively against the service root Vendor and Product, and against the
+	// manager Model when the service root doesn't identify the vendor
+	Identifiers []string
+	// SkipManagers are the substrings of the manager members that aren't scraped when there are several managers
+	SkipManagers []string
+	Quirks       common.Quirks
+}
+
+// RegisterVendor adds a vendor to the registry, it replaces the vendor with the same name
+func RegisterVendor(v *Vendor) {
+	vendorsMu.Lock()
+	defer vendorsMu.Unlock()
+
+	for i, existing := range vendors {
+		if existing.Name == v.Name {
+			vendors[i] = v
+			return
+		}
+	}
+	vendors = append(vendors, v)
+}
+
+// matchVendor returns the first vendor whose Oem keys or identifiers match, nil when none does
+func matchVendor(oemKeys []string, values ...string) *Vendor {
+	vendorsMu.RLock()
+	defer vendorsMu.RUnlock()
+
+	for _, v := range vendors {
+		for _, key := range oemKeys {
+			for _, k := range v.OemKeys {
+				if key == k {
+					return v
+				}
+			}
+		}
+	}
+
+	for _, value := range values {
+		value = strings.ToLower(value)
+		if value == "" {
+			continue
+		}
+		for _, v := range vendors {
+			for _, id := range v.Identifiers {
+				if strings.Contains(value, id) {
+					return v
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// manager returns the manager member to scrape, the members matching SkipManagers are only used when no other
+// member is left
+func (v *Vendor) manager(members []string) string {
+	if len(members) == 0 {
+		return ""
+	}
+
+	for _, member := range members {
+		skip := false
+		for _, s := range v.SkipManagers {
+			if strings.Contains(member, s) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			return member
+		}
+	}
+	return members[0]
+}
+
+// vendorCache keeps the vendor detected for each target
+type vendorCache struct {
+	mu      sync.RWMutex
+	vendors map[string]*Vendor
+}
+
+func (c *vendorCache) get(target string) (*Vendor, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.vendors[target]
+	return v, ok
+}
+
+func (c *vendorCache) set(target string, v *Vendor) {
+	c.mu.Lock()
+	c.vendors[target] = v
+	c.mu.Unlock()
+
+	common.TargetQuirks.Set(target, v.Quirks)
+}
+
+// detectVendor identifies the vendor of target from its service root, then from the model of its first manager
+// for the older firmwares that don't report their vendor in the service root. The vendor detected by a previous
+// scrape is kept when neither identifies it, DefaultVendor is used when no scrape did
+func detectVendor(root oem.ServiceRoot, managerURL func() string, target, profile string, client *retryablehttp.Client) *Vendor {
+	oemKeys := make([]string, 0, len(root.Oem))
+	for k := range root.Oem {
+		oemKeys = append(oemKeys, k)
+	}
+
+	v := matchVendor(oemKeys, root.Vendor, root.Product)
+	if v == nil {
+		if cached, ok := detectedVendors.get(target); ok {
+			return cached
+		}
+		if url := managerURL(); url != "" {
+			if mgr, err := getManagerMetadata(url, target, profile, client); err == nil {
+				v = matchVendor(nil, mgr.Model)
+			}
+		}
+	}
+	if v == nil {
+		v = DefaultVendor
+	}
+
+	detectedVendors.set(target, v)
+	return v
+}
+
+// getServiceRoot returns the service root at url
+func getServiceRoot(url, host string, profile string, client *retryablehttp.Client) (oem.ServiceRoot, error) {
+	var root oem.ServiceRoot
+
+	fetch := common.Fetch(url, host, profile, client)
+	body, err := fetch()
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidCredential) {
+			return root, common.ErrInvalidCredential
+		}
+		return root, fmt.Errorf("error fetching service root: %w", err)
+	}
+
+	err = json.Unmarshal(body, &root)
+	if err != nil {
+		return root, fmt.Errorf("error unmarshalling ServiceRoot struct - %s", err.Error())
+	}
+
+	return root, nil
+}
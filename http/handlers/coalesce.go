@@ -0,0 +1,132 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Coalescer shares one in-flight scrape between the identical scrape requests that arrive while it runs,
+// HA Prometheus pairs scrape the same target at nearly the same time and BMCs like iLO 4 can't cope with
+// the doubled sessions
+type Coalescer struct {
+	Registerer prometheus.Registerer
+
+	mu      sync.Mutex
+	flights map[string]*flight
+
+	coalesced *prometheus.CounterVec
+}
+
+type flight struct {
+	done chan struct{}
+	rec  *httptest.ResponseRecorder
+}
+
+// NewCoalescer returns a Coalescer with its metrics registered to reg
+func NewCoalescer(reg prometheus.Registerer) *Coalescer {
+	c := &Coalescer{
+		Registerer: reg,
+		flights:    make(map[string]*flight),
+		coalesced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fishymetrics_scrapes_coalesced_total",
+			Help: "The total number of scrape requests that were served by an identical in-flight scrape",
+		}, []string{"route"}),
+	}
+
+	c.Registerer.MustRegister(c.coalesced)
+
+	return c
+}
+
+// flightKey identifies identical scrape requests, the target, model, credential profile and components are
+// query parameters and the negotiated response format depends on the Accept headers
+func flightKey(r *http.Request) string {
+	return r.URL.Path + "?" + r.URL.Query().Encode() + "\n" + r.Header.Get("Accept") + "\n" + r.Header.Get("Accept-Encoding")
+}
+
+// do runs serve for the first request of a key and replays its response to the requests of the same key
+// that arrive until it is done. The shared scrape runs on a context detached from the first request so a
+// client that goes away doesn't fail the others, each request stops waiting when its own context is done
+func (c *Coalescer) do(w http.ResponseWriter, r *http.Request, serve func(http.ResponseWriter, *http.Request)) {
+	if c == nil {
+		serve(w, r)
+		return
+	}
+
+	key := flightKey(r)
+
+	c.mu.Lock()
+	f, ok := c.flights[key]
+	if ok {
+		c.mu.Unlock()
+		c.coalesced.WithLabelValues(r.URL.Path).Inc()
+	} else {
+		f = &flight{
+			done: make(chan struct{}),
+			rec:  httptest.NewRecorder(),
+		}
+		c.flights[key] = f
+		c.mu.Unlock()
+
+		ctx, cancel := context.WithoutCancel(r.Context()), context.CancelFunc(func() {})
+		if timeout := scrapeTimeout(r); timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		go func() {
+			defer func() {
+				cancel()
+				c.mu.Lock()
+				delete(c.flights, key)
+				c.mu.Unlock()
+				close(f.done)
+			}()
+			serve(f.rec, r.WithContext(ctx))
+		}()
+	}
+
+	select {
+	case <-f.done:
+		replay(w, f.rec)
+	case <-r.Context().Done():
+	}
+}
+
+// scrapeTimeout returns the timeout Prometheus sends with the scrape, the --timeout flag when there is none
+// and 0 when neither is set
+func scrapeTimeout(r *http.Request) time.Duration {
+	if v, err := strconv.ParseFloat(r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"), 64); err == nil && v > 0 {
+		return time.Duration(v * float64(time.Second))
+	}
+	return config.GetConfig().BMCTimeout
+}
+
+func replay(w http.ResponseWriter, rec *httptest.ResponseRecorder) {
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	w.Write(rec.Body.Bytes())
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Limiter caps how many scrapes run at once globally and per target, the scrapes over the global cap wait
+// in a bounded queue and the ones that can't be queued are shed so a burst of scrapes, like the one of a
+// Prometheus restart, can't exhaust the memory and file descriptors of the exporter
+type Limiter struct {
+	// MaxConcurrent is the number of scrapes running at once, unlimited when 0
+	MaxConcurrent int
+	// MaxPerTarget is the number of running and queued scrapes of a target, unlimited when 0
+	MaxPerTarget int
+	// MaxQueue is the number of scrapes waiting for one of the MaxConcurrent slots
+	MaxQueue int
+	// QueueTimeout is how long a scrape waits in the queue before it is shed
+	QueueTimeout time.Duration
+	Registerer   prometheus.Registerer
+
+	slots chan struct{}
+
+	mu      sync.Mutex
+	queued  int
+	targets map[string]int
+
+	queueDepth prometheus.Gauge
+	queueWait  *prometheus.HistogramVec
+	rejected   *prometheus.CounterVec
+}
+
+// NewLimiter returns a Limiter with its metrics registered to reg
+func NewLimiter(maxConcurrent, maxPerTarget, maxQueue int, queueTimeout time.Duration, reg prometheus.Registerer) *Limiter {
+	l := &Limiter{
+		MaxConcurrent: maxConcurrent,
+		MaxPerTarget:  maxPerTarget,
+		MaxQueue:      maxQueue,
+		QueueTimeout:  queueTimeout,
+		Registerer:    reg,
+		targets:       make(map[string]int),
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "fishymetrics_scrape_queue_depth",
+			Help: "Current number of scrape requests waiting for a concurrency slot",
+		}),
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fishymetrics_scrape_queue_wait_seconds",
+			Help:    "Histogram of the time scrape requests waited for a concurrency slot",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"route"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fishymetrics_scrapes_rejected_total",
+			Help: "The total number of scrape requests shed by the concurrency limits",
+		}, []string{"route", "reason"}),
+	}
+
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	l.Registerer.MustRegister(
+		l.queueDepth,
+		l.queueWait,
+		l.rejected,
+	)
+
+	return l
+}
+
+// do runs serve once the limits of the target allow it, otherwise it answers with a 429 when the target is
+// over its own cap and a 503 when the queue is full or the wait timed out
+func (l *Limiter) do(w http.ResponseWriter, r *http.Request, serve func()) {
+	if l == nil {
+		serve()
+		return
+	}
+
+	target := r.URL.Query().Get("target")
+
+	if l.MaxPerTarget > 0 {
+		l.mu.Lock()
+		if l.targets[target] >= l.MaxPerTarget {
+			l.mu.Unlock()
+			l.reject(w, r, "target_limit", http.StatusTooManyRequests)
+			return
+		}
+		l.targets[target]++
+		l.mu.Unlock()
+
+		defer func() {
+			l.mu.Lock()
+			l.targets[target]--
+			if l.targets[target] <= 0 {
+				delete(l.targets, target)
+			}
+			l.mu.Unlock()
+		}()
+	}
+
+	if l.slots != nil {
+		if ok := l.acquire(w, r); !ok {
+			return
+		}
+		defer func() { <-l.slots }()
+	}
+
+	serve()
+}
+
+// acquire takes a concurrency slot, waiting in the queue when there is none left
+func (l *Limiter) acquire(w http.ResponseWriter, r *http.Request) bool {
+	select {
+	case l.slots <- struct{}{}:
+		l.queueWait.WithLabelValues(r.URL.Path).Observe(0)
+		return true
+	default:
+	}
+
+	l.mu.Lock()
+	if l.queued >= l.MaxQueue {
+		l.mu.Unlock()
+		l.reject(w, r, "queue_full", http.StatusServiceUnavailable)
+		return false
+	}
+	l.queued++
+	l.queueDepth.Set(float64(l.queued))
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.queueDepth.Set(float64(l.queued))
+		l.mu.Unlock()
+	}()
+
+	start := time.Now()
+	timer := time.NewTimer(l.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.queueWait.WithLabelValues(r.URL.Path).Observe(time.Since(start).Seconds())
+		return true
+	case <-timer.C:
+		l.queueWait.WithLabelValues(r.URL.Path).Observe(time.Since(start).Seconds())
+		l.reject(w, r, "queue_timeout", http.StatusServiceUnavailable)
+		return false
+	case <-r.Context().Done():
+		l.queueWait.WithLabelValues(r.URL.Path).Observe(time.Since(start).Seconds())
+		return false
+	}
+}
+
+func (l *Limiter) reject(w http.ResponseWriter, r *http.Request, reason string, code int) {
+	zap.L().Warn("scrape request rejected", zap.String("reason", reason), zap.String("target", r.URL.Query().Get("target")),
+		zap.Any("trace_id", r.Context().Value(logging.TraceIDKey("traceID"))))
+	l.rejected.WithLabelValues(r.URL.Path, reason).Inc()
+
+	// the queue is expected to have drained after about one queue timeout
+	retryAfter := int(math.Max(1, math.Ceil(l.QueueTimeout.Seconds())))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, http.StatusText(code), code)
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/exporter"
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	authBasic   = "basic"
+	authSession = "session"
+)
+
+var (
+	// moduleExcludes maps the components of the module excludes to the keys of the exporter excludes
+	moduleExcludes = map[exporter.ComponentType]string{
+		exporter.ComponentDrives:   "drive",
+		exporter.ComponentFirmware: "firmware",
+	}
+)
+
+// Module is a named set of scrape settings picked by the module query parameter, the query parameters of
+// the request take precedence over the module
+type Module struct {
+	Model             string `yaml:"model,omitempty"`
+	CredentialProfile string `yaml:"credentialProfile,omitempty"`
+	// Components uses a partial scrape of the listed components when set
+	Components []string `yaml:"components,omitempty"`
+	// Excludes are the regexes of the drive and firmware modules to exclude, keyed by component
+	Excludes map[string]string `yaml:"excludes,omitempty"`
+	// Timeout of each request sent to the BMC
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Concurrency is the number of requests sent to the BMC at once
+	Concurrency int                 `yaml:"concurrency,omitempty"`
+	TLS         *common.TLSSettings `yaml:"tls,omitempty"`
+	// Auth is basic or session, session authentication creates one Redfish session per target
+	Auth    string   `yaml:"auth,omitempty"`
+	Plugins []string `yaml:"plugins,omitempty"`
+
+	excludes map[string]interface{}
+}
+
+// ConfigFile is the yaml file of --config.file
+type ConfigFile struct {
+	Modules map[string]*Module `yaml:"modules"`
+	// TargetTLS are the TLS settings of the targets matching a regex, the first match is used for the
+	// scrapes whose module doesn't set any
+	TargetTLS []*common.TargetTLSSettings `yaml:"targetTLS,omitempty"`
+	// Metrics are the metric mappings collected by the mappings component
+	Metrics []*exporter.MetricMapping `yaml:"metrics,omitempty"`
+}
+
+// LoadConfigFile reads and validates the config file, the module excludes are added to the global excludes
+func LoadConfigFile(path string, excludes map[string]interface{}) (*ConfigFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file - %s", err.Error())
+	}
+
+	var cf ConfigFile
+	if err := yaml.Unmarshal(b, &cf); err != nil {
+		return nil, fmt.Errorf("error unmarshalling config file - %s", err.Error())
+	}
+
+	for name, m := range cf.Modules {
+		if m == nil {
+			m = &Module{}
+			cf.Modules[name] = m
+		}
+		if err := m.init(excludes); err != nil {
+			return nil, fmt.Errorf("module %s - %s", name, err.Error())
+		}
+	}
+
+	for i, t := range cf.TargetTLS {
+		if err := t.Load(); err != nil {
+			return nil, fmt.Errorf("targetTLS #%d - %s", i+1, err.Error())
+		}
+	}
+
+	names := make(map[string]bool, len(cf.Metrics))
+	for i, m := range cf.Metrics {
+		if err := m.Load(); err != nil {
+			return nil, fmt.Errorf("metrics #%d - %s", i+1, err.Error())
+		}
+		if names[m.Name] {
+			return nil, fmt.Errorf("metrics #%d - duplicate metric name %s", i+1, m.Name)
+		}
+		names[m.Name] = true
+	}
+
+	return &cf, nil
+}
+
+// init validates the module and compiles its excludes
+func (m *Module) init(excludes map[string]interface{}) error {
+	if len(m.Components) > 0 {
+		if _, err := exporter.ParseComponents(strings.Join(m.Components, ",")); err != nil {
+			return err
+		}
+	}
+
+	if len(m.Excludes) > 0 {
+		m.excludes = make(map[string]interface{}, len(excludes)+len(m.Excludes))
+		for k, v := range excludes {
+			m.excludes[k] = v
+		}
+		for component, pattern := range m.Excludes {
+			key, ok := moduleExcludes[exporter.ComponentType(component)]
+			if !ok {
+				return fmt.Errorf("excludes of component %s are not supported, valid components are: drives, firmware", component)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid %s excludes - %s", component, err.Error())
+			}
+			m.excludes[key] = re
+		}
+	}
+
+	if m.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if m.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative")
+	}
+
+	if m.TLS != nil {
+		if err := m.TLS.Load(); err != nil {
+			return fmt.Errorf("tls - %s", err.Error())
+		}
+	}
+
+	switch m.Auth {
+	case "", authBasic, authSession:
+	default:
+		return fmt.Errorf("invalid auth %s, valid auth modes are: basic, session", m.Auth)
+	}
+
+	if _, err := exporter.ParsePlugins(strings.Join(m.Plugins, ",")); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// module returns the module picked by the request, nil when it doesn't pick one. An unknown module is
+// answered with a 400
+func (cfg *ScrapeConfig) module(w http.ResponseWriter, r *http.Request) (*Module, bool) {
+	name := r.URL.Query().Get("module")
+	if name == "" {
+		return nil, true
+	}
+
+	m, ok := cfg.Modules[name]
+	if !ok {
+		zap.L().Error("unknown module", zap.String("module", name),
+			zap.Any("trace_id", r.Context().Value(logging.TraceIDKey("traceID"))))
+		http.Error(w, fmt.Sprintf("unknown module %s", name), http.StatusBadRequest)
+		return nil, false
+	}
+
+	return m, true
+}
+
+// partial reports whether the module scrapes a subset of the components
+func (m *Module) partial() bool {
+	return m != nil && len(m.Components) > 0
+}
+
+// apply returns the request with the module settings filled in the query parameters it didn't set and
+// its client settings in the context, the returned config has the excludes of the module
+func (m *Module) apply(r *http.Request, cfg *ScrapeConfig) (*http.Request, *ScrapeConfig) {
+	if m == nil {
+		return r, cfg
+	}
+
+	query := r.URL.Query()
+	setDefault := func(key, value string) {
+		if value != "" && query.Get(key) == "" {
+			query.Set(key, value)
+		}
+	}
+	setDefault("model", m.Model)
+	setDefault("credential_profile", m.CredentialProfile)
+	setDefault("components", strings.Join(m.Components, ","))
+	setDefault("plugins", strings.Join(m.Plugins, ","))
+
+	r = r.Clone(exporter.WithScrapeOptions(r.Context(), exporter.ScrapeOptions{
+		Timeout:     m.Timeout,
+		Concurrency: m.Concurrency,
+		TLS:         m.TLS,
+		SessionAuth: m.Auth == authSession,
+	}))
+	r.URL.RawQuery = query.Encode()
+
+	if m.excludes != nil {
+		c := *cfg
+		c.Excludes = m.excludes
+		cfg = &c
+	}
+
+	return r, cfg
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/comcast/fishymetrics/exporter"
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"go.uber.org/zap"
+	// the plugins register themselves with the exporter by name
+	_ "github.com/comcast/fishymetrics/plugins/nuova"
+)
+
+// PluginsHandler handles GET /plugins requests, it lists the plugins the plugins query parameter can pick
+func PluginsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(exporter.Plugins()); err != nil {
+		zap.L().Error("error encoding plugins", zap.Error(err),
+			zap.Any("trace_id", r.Context().Value(logging.TraceIDKey("traceID"))))
+	}
+}
@@ -0,0 +1,390 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/comcast/fishymetrics/middleware/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// pollJitter is the fraction of the interval every poll is randomly moved by so the targets sharing
+	// an interval don't hit their BMCs in lockstep
+	pollJitter = 0.1
+)
+
+// PollTarget is a target scraped in the background by the Poller
+type PollTarget struct {
+	Target            string        `yaml:"target"`
+	Model             string        `yaml:"model,omitempty"`
+	CredentialProfile string        `yaml:"credentialProfile,omitempty"`
+	Interval          time.Duration `yaml:"interval,omitempty"`
+	Module            string        `yaml:"module,omitempty"`
+	// Components uses a partial scrape of the comma separated components when set
+	Components string `yaml:"components,omitempty"`
+}
+
+type pollTargets struct {
+	Targets []PollTarget `yaml:"targets"`
+}
+
+// pollParams are the query parameters that change what a scrape collects, a scrape of a polled target is
+// only served from the poll when the ones it sets are the ones the target is polled with
+var pollParams = []string{"module", "model", "components", "plugins", "credential_profile"}
+
+// params returns the pollParams the target is polled with
+func (t PollTarget) params() map[string]string {
+	return map[string]string{
+		"module":             t.Module,
+		"model":              t.Model,
+		"components":         t.Components,
+		"credential_profile": t.CredentialProfile,
+	}
+}
+
+// LoadPollTargets reads the targets file, targets without an interval are polled every defaultInterval
+func LoadPollTargets(path string, defaultInterval time.Duration) ([]PollTarget, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading poller targets file - %s", err.Error())
+	}
+
+	var pt pollTargets
+	if err := yaml.Unmarshal(b, &pt); err != nil {
+		return nil, fmt.Errorf("error unmarshalling poller targets file - %s", err.Error())
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range pt.Targets {
+		if t.Target == "" {
+			return nil, fmt.Errorf("poller target #%d has no target", i+1)
+		}
+		if seen[t.Target] {
+			return nil, fmt.Errorf("poller target %s is listed more than once", t.Target)
+		}
+		seen[t.Target] = true
+
+		if t.Interval <= 0 {
+			pt.Targets[i].Interval = defaultInterval
+		}
+	}
+
+	return pt.Targets, nil
+}
+
+// Poller scrapes a list of targets on their own schedule and keeps the results, Prometheus scrapes are then
+// served from memory so the BMC load doesn't depend on how many Prometheus replicas scrape fishymetrics
+type Poller struct {
+	Targets []PollTarget
+
+	mu      sync.Mutex
+	scrape  http.Handler
+	partial http.Handler
+	results map[string]*pollResult
+	// stops holds the stop channel of each running poll, nil until Run is called
+	stops map[string]chan struct{}
+	polls sync.WaitGroup
+}
+
+type pollResult struct {
+	target   PollTarget
+	families []*dto.MetricFamily
+	polled   time.Time
+}
+
+// NewPoller returns a Poller that scrapes targets with the scrape handlers configured by cfg
+func NewPoller(cfg ScrapeConfig, targets []PollTarget) *Poller {
+	p := &Poller{
+		Targets: targets,
+		results: make(map[string]*pollResult),
+	}
+	p.Reload(cfg, targets)
+
+	return p
+}
+
+// Reload makes the next polls use the scrape handlers configured by cfg and polls targets, the polls and the
+// results of the targets that were removed or changed are dropped
+func (p *Poller) Reload(cfg ScrapeConfig, targets []PollTarget) {
+	// the background scrapes must reach the BMCs instead of being served by the poller
+	cfg.Poller = nil
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.scrape = logging.LoggingHandler(ScrapeHandler(&cfg))
+	p.partial = logging.LoggingHandler(PartialScrapeHandler(&cfg))
+
+	for _, t := range p.Targets {
+		if slices.Contains(targets, t) {
+			continue
+		}
+		delete(p.results, t.Target)
+		if stop, ok := p.stops[t.Target]; ok {
+			close(stop)
+			delete(p.stops, t.Target)
+		}
+	}
+	p.Targets = targets
+
+	if p.stops != nil {
+		p.start()
+	}
+}
+
+// start runs the polls of the targets that aren't polled yet, p.mu must be held
+func (p *Poller) start() {
+	for _, t := range p.Targets {
+		if _, ok := p.stops[t.Target]; ok {
+			continue
+		}
+		stop := make(chan struct{})
+		p.stops[t.Target] = stop
+
+		p.polls.Add(1)
+		go func(t PollTarget) {
+			defer p.polls.Done()
+			p.poll(t, stop)
+		}(t)
+	}
+}
+
+// Run polls every target until done is closed
+func (p *Poller) Run(done chan bool, wg *sync.WaitGroup) {
+	log := zap.L()
+	defer wg.Done()
+
+	p.mu.Lock()
+	p.stops = make(map[string]chan struct{})
+	p.start()
+	log.Info("started polling targets", zap.Int("targets", len(p.Targets)))
+	p.mu.Unlock()
+
+	<-done
+	log.Info("stopping poller go routine")
+	p.mu.Lock()
+	for _, stop := range p.stops {
+		close(stop)
+	}
+	p.stops = nil
+	p.mu.Unlock()
+	p.polls.Wait()
+}
+
+// poll scrapes t every interval until stop is closed, the first scrape happens at a random point of the first
+// interval
+func (p *Poller) poll(t PollTarget, stop <-chan struct{}) {
+	timer := time.NewTimer(rand.N(t.Interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+
+		p.scrapeTarget(t, stop)
+		timer.Reset(jitter(t.Interval))
+	}
+}
+
+// jitter returns interval randomly moved by up to pollJitter of it
+func jitter(interval time.Duration) time.Duration {
+	spread := time.Duration(float64(interval) * pollJitter)
+	if spread <= 0 {
+		return interval
+	}
+	return interval - spread + rand.N(2*spread)
+}
+
+// scrapeTarget runs the scrape handler for t and stores the metrics it returned unless stop was closed meanwhile,
+// the target was removed by a reload
+func (p *Poller) scrapeTarget(t PollTarget, stop <-chan struct{}) {
+	log := zap.L()
+
+	query := url.Values{}
+	query.Set("target", t.Target)
+	if t.Model != "" {
+		query.Set("model", t.Model)
+	}
+	if t.CredentialProfile != "" {
+		query.Set("credential_profile", t.CredentialProfile)
+	}
+
+	if t.Module != "" {
+		query.Set("module", t.Module)
+	}
+
+	p.mu.Lock()
+	h, path := p.scrape, "/scrape"
+	if t.Components != "" {
+		h, path = p.partial, "/scrape/partial"
+		query.Set("components", t.Components)
+	}
+	p.mu.Unlock()
+
+	req := httptest.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	// a poll shed by the scrape limits says nothing about the target, the last poll is kept
+	if (rec.Code == http.StatusTooManyRequests || rec.Code == http.StatusServiceUnavailable) && rec.Header().Get("Retry-After") != "" {
+		p.mu.Lock()
+		_, polled := p.results[t.Target]
+		p.mu.Unlock()
+		if polled {
+			log.Warn("poll of target rejected by the scrape limits, keeping the last poll", zap.String("target", t.Target),
+				zap.Int("status", rec.Code))
+			return
+		}
+	}
+
+	var families []*dto.MetricFamily
+	if rec.Code == http.StatusOK {
+		parsed, err := new(expfmt.TextParser).TextToMetricFamilies(rec.Body)
+		if err != nil {
+			log.Error("error parsing polled metrics", zap.Error(err), zap.String("target", t.Target))
+		}
+		for _, f := range parsed {
+			families = append(families, f)
+		}
+	} else {
+		log.Error("error polling target", zap.String("target", t.Target), zap.Int("status", rec.Code),
+			zap.String("response", rec.Body.String()))
+	}
+
+	// a failed scrape without metrics is still reported as down
+	if _, ok := gaugeValue(families, upMetricName); !ok {
+		families = append(families, gaugeFamily(upMetricName, upMetricHelp, 0))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-stop:
+		return
+	default:
+	}
+	p.results[t.Target] = &pollResult{
+		target:   t,
+		families: sortFamilies(families),
+		polled:   time.Now(),
+	}
+}
+
+// gatherer returns the metrics of the last poll of the target of query, false when the target is not polled,
+// wasn't polled yet or query sets other pollParams than the ones it is polled with
+func (p *Poller) gatherer(query url.Values) (prometheus.Gatherer, bool) {
+	if p == nil || len(query["target"]) != 1 {
+		return nil, false
+	}
+
+	p.mu.Lock()
+	r, ok := p.results[query.Get("target")]
+	p.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	params := r.target.params()
+	for _, key := range pollParams {
+		if v := query.Get(key); v != "" && v != params[key] {
+			return nil, false
+		}
+	}
+
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		return r.families, nil
+	}), true
+}
+
+// AllHandler handles GET /scrape/all requests, it serves the last poll of every target with a target label
+func (p *Poller) AllHandler(w http.ResponseWriter, r *http.Request) {
+	g := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		targets := make([]string, 0, len(p.results))
+		for target := range p.results {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		merged := make(map[string]*dto.MetricFamily)
+		for _, target := range targets {
+			for _, f := range p.results[target].families {
+				m, ok := merged[f.GetName()]
+				if !ok {
+					m = &dto.MetricFamily{Name: f.Name, Help: f.Help, Type: f.Type}
+					merged[f.GetName()] = m
+				}
+				for _, metric := range f.GetMetric() {
+					m.Metric = append(m.Metric, withTargetLabel(metric, target))
+				}
+			}
+		}
+
+		families := make([]*dto.MetricFamily, 0, len(merged))
+		for _, f := range merged {
+			families = append(families, f)
+		}
+		return sortFamilies(families), nil
+	})
+
+	promhttp.HandlerFor(g, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// withTargetLabel returns a copy of m with the target label added, a target label of m is renamed to
+// exported_target like Prometheus does on conflicting target labels
+func withTargetLabel(m *dto.Metric, target string) *dto.Metric {
+	name, exported := "target", "exported_target"
+	labels := make([]*dto.LabelPair, 0, len(m.GetLabel())+1)
+	labels = append(labels, &dto.LabelPair{Name: &name, Value: &target})
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			l = &dto.LabelPair{Name: &exported, Value: l.Value}
+		}
+		labels = append(labels, l)
+	}
+
+	return &dto.Metric{
+		Label:       labels,
+		Gauge:       m.Gauge,
+		Counter:     m.Counter,
+		Summary:     m.Summary,
+		Untyped:     m.Untyped,
+		Histogram:   m.Histogram,
+		TimestampMs: m.TimestampMs,
+	}
+}
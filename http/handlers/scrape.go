@@ -22,12 +22,13 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 
 	"github.com/comcast/fishymetrics/common"
+	"github.com/comcast/fishymetrics/events"
 	"github.com/comcast/fishymetrics/exporter"
 	"github.com/comcast/fishymetrics/exporter/moonshot"
 	"github.com/comcast/fishymetrics/middleware/logging"
-	"github.com/comcast/fishymetrics/plugins/nuova"
 	fishy_vault "github.com/comcast/fishymetrics/vault"
 	"go.uber.org/zap"
 
@@ -42,19 +43,92 @@ type ScrapeConfig struct {
 	Excludes           map[string]interface{}
 	URLExtraParamsMap  map[string]string
 	ExtraParamsAliases map[string]string
+	// Events subscribes to the EventService of the scraped targets, nil when disabled
+	Events *events.Subscriber
+	// Stale serves the last successful metrics of targets that fail to scrape, nil when disabled
+	Stale *StaleCache
+	// Poller serves the targets scraped in the background, nil when disabled
+	Poller *Poller
+	// Coalescer shares in-flight scrapes between identical requests, nil when disabled
+	Coalescer *Coalescer
+	// Limiter caps the concurrent scrapes, nil when unlimited
+	Limiter *Limiter
+	// Modules are the named scrape settings of the config file picked by the module query parameter
+	Modules map[string]*Module
 }
 
-// ScrapeHandler handles GET /scrape requests
+// CurrentConfig holds the ScrapeConfig in use, a reload swaps it atomically and the scrapes already running
+// finish with the one they started with
+type CurrentConfig struct {
+	cfg atomic.Pointer[ScrapeConfig]
+}
+
+// NewCurrentConfig returns a CurrentConfig holding cfg
+func NewCurrentConfig(cfg *ScrapeConfig) *CurrentConfig {
+	c := &CurrentConfig{}
+	c.cfg.Store(cfg)
+	return c
+}
+
+// Load returns the ScrapeConfig in use
+func (c *CurrentConfig) Load() *ScrapeConfig {
+	return c.cfg.Load()
+}
+
+// Store replaces the ScrapeConfig in use
+func (c *CurrentConfig) Store(cfg *ScrapeConfig) {
+	c.cfg.Store(cfg)
+}
+
+// Handler returns a handler serving each request with the handler h builds from the ScrapeConfig in use
+func (c *CurrentConfig) Handler(h func(*ScrapeConfig) http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h(c.Load())(w, r)
+	}
+}
+
+// ScrapeHandler handles GET /scrape requests, the requests picking a module with components are partial scrapes
 func ScrapeHandler(cfg *ScrapeConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		handler(r.Context(), w, r, cfg)
+		// polled targets are served from their last background scrape
+		if g, ok := cfg.Poller.gatherer(r.URL.Query()); ok {
+			promhttp.HandlerFor(g, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+			return
+		}
+
+		m, ok := cfg.module(w, r)
+		if !ok {
+			return
+		}
+		r, cfg := m.apply(r, cfg)
+
+		scrape := handler
+		if m.partial() {
+			scrape = partialHandler
+		}
+
+		cfg.Coalescer.do(w, r, func(w http.ResponseWriter, r *http.Request) {
+			cfg.Limiter.do(w, r, func() {
+				scrape(r.Context(), w, r, cfg)
+			})
+		})
 	}
 }
 
 // PartialScrapeHandler handles GET /scrape/partial requests
 func PartialScrapeHandler(cfg *ScrapeConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		partialHandler(r.Context(), w, r, cfg)
+		m, ok := cfg.module(w, r)
+		if !ok {
+			return
+		}
+		r, cfg := m.apply(r, cfg)
+
+		cfg.Coalescer.do(w, r, func(w http.ResponseWriter, r *http.Request) {
+			cfg.Limiter.do(w, r, func() {
+				partialHandler(r.Context(), w, r, cfg)
+			})
+		})
 	}
 }
 
@@ -79,13 +153,12 @@ func handler(ctx context.Context, w http.ResponseWriter, r *http.Request, cfg *S
 
 	// optional query param for external plugins which executes non redfish API calls to the device.
 	// this is a comma separated list of strings
-	plugins := strings.Split(query.Get("plugins"), ",")
-	var plugs []exporter.Plugin
-	for _, p := range plugins {
-		if p == "nuova" {
-			plugs = append(plugs, &nuova.NuovaPlugin{})
-			log.Debug("nuova plugin added", zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-		}
+	plugs, err := exporter.ParsePlugins(query.Get("plugins"))
+	if err != nil {
+		log.Error("invalid plugins parameter", zap.Error(err), zap.String("plugins", query.Get("plugins")),
+			zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	log.Info("started scrape",
@@ -119,6 +192,7 @@ func handler(ctx context.Context, w http.ResponseWriter, r *http.Request, cfg *S
 	if !ok {
 		return
 	}
+	ctx = exporter.WithTargetTLS(ctx, target)
 
 	// check if credentials script is configured
 	if cfg.CredentialsScript != "" {
@@ -153,13 +227,20 @@ func handler(ctx context.Context, w http.ResponseWriter, r *http.Request, cfg *S
 
 	if err != nil {
 		log.Error("failed to create chassis exporter", zap.Error(err), zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+		if cfg.Stale.serveStale(w, r, staleKey(r.URL.Path, query)) {
+			return
+		}
 		http.Error(w, fmt.Sprintf("failed to create chassis exporter - %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
 
+	if cfg.Events != nil && model != "moonshot" {
+		cfg.Events.Ensure(ctx, target, credProf)
+	}
+
 	registry.MustRegister(exp)
 	// Delegate http serving to Prometheus client library, which will call collector.Collect.
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	h := promhttp.HandlerFor(cfg.Stale.gatherer(staleKey(r.URL.Path, query), registry), promhttp.HandlerOpts{})
 	h.ServeHTTP(w, r)
 }
 
@@ -220,7 +301,7 @@ func partialHandler(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	if componentsStr == "" {
 		log.Error("'components' parameter not set",
 			zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-		http.Error(w, "'components' parameter is required. Valid components are: thermal, power, memory, processor, drives, storage_controller, firmware, system",
+		http.Error(w, "'components' parameter is required. Valid components are: "+strings.Join(exporter.ComponentNames(), ", "),
 			http.StatusBadRequest)
 		return
 	}
@@ -240,13 +321,12 @@ func partialHandler(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	credProf := query.Get("credential_profile")
 
 	// optional query param for external plugins
-	plugins := strings.Split(query.Get("plugins"), ",")
-	var plugs []exporter.Plugin
-	for _, p := range plugins {
-		if p == "nuova" {
-			plugs = append(plugs, &nuova.NuovaPlugin{})
-			log.Debug("nuova plugin added", zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
-		}
+	plugs, err := exporter.ParsePlugins(query.Get("plugins"))
+	if err != nil {
+		log.Error("invalid plugins parameter", zap.Error(err), zap.String("plugins", query.Get("plugins")),
+			zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	log.Info("started partial scrape",
@@ -281,6 +361,7 @@ func partialHandler(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	if !ok {
 		return
 	}
+	ctx = exporter.WithTargetTLS(ctx, target)
 
 	// check if credentials script is configured
 	if cfg.CredentialsScript != "" {
@@ -320,13 +401,20 @@ func partialHandler(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	if err != nil {
 		log.Error("failed to create partial chassis exporter", zap.Error(err),
 			zap.Any("trace_id", ctx.Value(logging.TraceIDKey("traceID"))))
+		if cfg.Stale.serveStale(w, r, staleKey(r.URL.Path, query)) {
+			return
+		}
 		http.Error(w, fmt.Sprintf("failed to create partial chassis exporter - %s", err.Error()),
 			http.StatusInternalServerError)
 		return
 	}
 
+	if cfg.Events != nil {
+		cfg.Events.Ensure(ctx, target, credProf)
+	}
+
 	registry.MustRegister(exp)
 	// Delegate http serving to Prometheus client library, which will call collector.Collect.
-	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	h := promhttp.HandlerFor(cfg.Stale.gatherer(staleKey(r.URL.Path, query), registry), promhttp.HandlerOpts{})
 	h.ServeHTTP(w, r)
 }
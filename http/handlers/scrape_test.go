@@ -2,14 +2,24 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 )
 
 // Ensure invalid proxy_host is rejected early with 400.
@@ -218,3 +228,595 @@ func writeCredentialsScript(t *testing.T, body string) string {
 	}
 	return script
 }
+
+func Test_StaleCache_ServesLastSuccess(t *testing.T) {
+	newRegistry := func(up float64) *prometheus.Registry {
+		registry := prometheus.NewRegistry()
+		upGauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: upMetricName, Help: upMetricHelp})
+		upGauge.Set(up)
+		registry.MustRegister(upGauge)
+		if up == 1 {
+			fan := prometheus.NewGauge(prometheus.GaugeOpts{Name: "redfish_thermal_fan_speed", Help: "fan speed"})
+			fan.Set(42)
+			registry.MustRegister(fan)
+		}
+		return registry
+	}
+
+	c := NewStaleCache(time.Minute)
+	key := staleKey("/scrape", url.Values{"target": []string{"1.2.3.4"}})
+
+	families, err := c.gatherer(key, newRegistry(1)).Gather()
+	if err != nil {
+		t.Fatalf("Gather error: %v", err)
+	}
+	if _, ok := gaugeValue(families, lastSuccessMetricName); !ok {
+		t.Fatalf("successful scrape is missing %s", lastSuccessMetricName)
+	}
+
+	// a failed scrape serves the last successful metrics with the failed redfish_up
+	families, err = c.gatherer(key, newRegistry(2)).Gather()
+	if err != nil {
+		t.Fatalf("Gather error: %v", err)
+	}
+	if up, _ := gaugeValue(families, upMetricName); up != 2 {
+		t.Fatalf("redfish_up = %v, want 2", up)
+	}
+	if fan, ok := gaugeValue(families, "redfish_thermal_fan_speed"); !ok || fan != 42 {
+		t.Fatalf("redfish_thermal_fan_speed = %v, want 42", fan)
+	}
+
+	// the exporter could not be created
+	rr := httptest.NewRecorder()
+	if !c.serveStale(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil), key) {
+		t.Fatalf("serveStale = false, want true")
+	}
+	for _, want := range []string{"redfish_up 0", "redfish_thermal_fan_speed 42", lastSuccessMetricName} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Fatalf("body = %q, want %q", rr.Body.String(), want)
+		}
+	}
+
+	// other targets and expired metrics are not served
+	if c.serveStale(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/scrape", nil), staleKey("/scrape", url.Values{"target": []string{"5.6.7.8"}})) {
+		t.Fatalf("serveStale of unknown target = true, want false")
+	}
+	c.scrapes[key].success = time.Now().Add(-2 * time.Minute)
+	families, err = c.gatherer(key, newRegistry(0)).Gather()
+	if err != nil {
+		t.Fatalf("Gather error: %v", err)
+	}
+	if _, ok := gaugeValue(families, "redfish_thermal_fan_speed"); ok {
+		t.Fatalf("expired metrics were served")
+	}
+}
+
+func TestLoadPollTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.yaml")
+	targets := `
+targets:
+  - target: 1.2.3.4
+    model: dl360
+    credentialProfile: profile1
+    interval: 30s
+  - target: 5.6.7.8
+    components: thermal,power
+`
+	if err := os.WriteFile(path, []byte(targets), 0600); err != nil {
+		t.Fatalf("write targets: %v", err)
+	}
+
+	got, err := LoadPollTargets(path, time.Minute)
+	if err != nil {
+		t.Fatalf("LoadPollTargets error: %v", err)
+	}
+	want := []PollTarget{
+		{Target: "1.2.3.4", Model: "dl360", CredentialProfile: "profile1", Interval: 30 * time.Second},
+		{Target: "5.6.7.8", Interval: time.Minute, Components: "thermal,power"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("targets = %+v, want %+v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("targets:\n  - target: 1.2.3.4\n  - target: 1.2.3.4\n"), 0600); err != nil {
+		t.Fatalf("write targets: %v", err)
+	}
+	if _, err := LoadPollTargets(path, time.Minute); err == nil {
+		t.Fatal("LoadPollTargets returned nil error for a duplicate target")
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := jitter(time.Minute); d < 54*time.Second || d >= 66*time.Second {
+			t.Fatalf("jitter = %s, want within 10%% of 1m", d)
+		}
+	}
+}
+
+func Test_Poller_ServesPolledTargets(t *testing.T) {
+	script := writeCredentialsScript(t, "exit 42\n")
+	p := NewPoller(ScrapeConfig{CredentialsScript: script}, []PollTarget{{Target: "1.2.3.4", Interval: time.Minute}})
+
+	// a failed poll is reported as down
+	p.scrapeTarget(p.Targets[0], nil)
+
+	// the metrics of the target can have a target label of their own
+	fan := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "redfish_thermal_fan_speed", Help: "fan speed"}, []string{"target"})
+	fan.WithLabelValues("bmc-a").Set(42)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(fan)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather error: %v", err)
+	}
+	p.results["5.6.7.8"] = &pollResult{
+		target:   PollTarget{Target: "5.6.7.8", Components: "thermal"},
+		families: append(families, gaugeFamily(upMetricName, upMetricHelp, 1)),
+		polled:   time.Now(),
+	}
+
+	h := ScrapeHandler(&ScrapeConfig{Poller: p, CredentialsScript: script})
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "redfish_up 0") {
+		t.Fatalf("status = %d body = %q, want polled redfish_up 0", rr.Code, rr.Body.String())
+	}
+
+	// the scrapes setting the parameters of the poll are served from it, the other ones are scraped live
+	for query, polled := range map[string]bool{
+		"target=5.6.7.8&components=thermal":   true,
+		"target=5.6.7.8&components=power":     false,
+		"target=1.2.3.4&model=dl360":          false,
+		"target=1.2.3.4&plugins=nuova":        false,
+		"target=1.2.3.4&credential_profile=x": false,
+	} {
+		rr = httptest.NewRecorder()
+		h(rr, httptest.NewRequest(http.MethodGet, "/scrape?"+query, nil))
+		if got := rr.Code == http.StatusOK; got != polled {
+			t.Fatalf("%s status = %d body = %q, want served from the poll %v", query, rr.Code, rr.Body.String(), polled)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	p.AllHandler(rr, httptest.NewRequest(http.MethodGet, "/scrape/all", nil))
+	for _, want := range []string{
+		`redfish_up{target="1.2.3.4"} 0`,
+		`redfish_up{target="5.6.7.8"} 1`,
+		`redfish_thermal_fan_speed{target="5.6.7.8",exported_target="bmc-a"} 42`,
+	} {
+		if !strings.Contains(rr.Body.String(), want) {
+			t.Fatalf("body = %q, want %q", rr.Body.String(), want)
+		}
+	}
+}
+
+func Test_Poller_KeepsPollShedByLimiter(t *testing.T) {
+	l := NewLimiter(1, 0, 0, time.Second, prometheus.NewRegistry())
+	p := NewPoller(ScrapeConfig{Limiter: l}, []PollTarget{{Target: "1.2.3.4", Interval: time.Minute}})
+	p.results["1.2.3.4"] = &pollResult{
+		target:   p.Targets[0],
+		families: []*dto.MetricFamily{gaugeFamily(upMetricName, upMetricHelp, 1)},
+		polled:   time.Now(),
+	}
+
+	// every slot is taken and the queue is full, the poll is shed with a 503
+	l.slots <- struct{}{}
+	p.scrapeTarget(p.Targets[0], nil)
+	<-l.slots
+
+	if got := testutil.ToFloat64(l.rejected.WithLabelValues("/scrape", "queue_full")); got != 1 {
+		t.Fatalf("rejected = %v, want 1", got)
+	}
+	if up, _ := gaugeValue(p.results["1.2.3.4"].families, upMetricName); up != 1 {
+		t.Fatalf("redfish_up = %v, want the last good poll", up)
+	}
+}
+
+func Test_Poller_Reload(t *testing.T) {
+	script := writeCredentialsScript(t, "exit 42\n")
+	cfg := ScrapeConfig{CredentialsScript: script}
+	targets := []PollTarget{
+		{Target: "1.2.3.4", Interval: time.Hour},
+		{Target: "5.6.7.8", Interval: time.Hour},
+		{Target: "9.9.9.9", Interval: time.Hour},
+	}
+	p := NewPoller(cfg, targets)
+
+	var wg sync.WaitGroup
+	done := make(chan bool)
+	wg.Add(1)
+	go p.Run(done, &wg)
+	t.Cleanup(func() {
+		close(done)
+		wg.Wait()
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p.mu.Lock()
+		running := len(p.stops)
+		p.mu.Unlock()
+		if running == len(targets) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("polls running = %d, want %d", running, len(targets))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, target := range targets {
+		p.scrapeTarget(target, nil)
+	}
+	p.mu.Lock()
+	removedStop := p.stops["5.6.7.8"]
+	p.mu.Unlock()
+
+	// 5.6.7.8 is removed and 9.9.9.9 is polled with other settings, 10.0.0.1 is added
+	p.Reload(cfg, []PollTarget{
+		{Target: "1.2.3.4", Interval: time.Hour},
+		{Target: "9.9.9.9", Interval: time.Hour, Components: "thermal"},
+		{Target: "10.0.0.1", Interval: time.Hour},
+	})
+
+	p.mu.Lock()
+	_, kept := p.results["1.2.3.4"]
+	_, removed := p.results["5.6.7.8"]
+	_, changed := p.results["9.9.9.9"]
+	stops := len(p.stops)
+	p.mu.Unlock()
+	if !kept || removed || changed {
+		t.Fatalf("results of 1.2.3.4 %v, 5.6.7.8 %v, 9.9.9.9 %v, want only the unchanged target", kept, removed, changed)
+	}
+	if stops != 3 {
+		t.Fatalf("polls running = %d, want 3", stops)
+	}
+
+	// a poll of a removed target that was running during the reload isn't stored
+	p.scrapeTarget(PollTarget{Target: "5.6.7.8", Interval: time.Hour}, removedStop)
+	rr := httptest.NewRecorder()
+	p.AllHandler(rr, httptest.NewRequest(http.MethodGet, "/scrape/all", nil))
+	if strings.Contains(rr.Body.String(), "5.6.7.8") {
+		t.Fatalf("body = %q, want no removed target", rr.Body.String())
+	}
+}
+
+func Test_Coalescer_SharesInFlightScrape(t *testing.T) {
+	c := NewCoalescer(prometheus.NewRegistry())
+
+	var calls int32
+	release := make(chan struct{})
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("redfish_up 1\n"))
+	}
+
+	const requests = 3
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, requests)
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rr *httptest.ResponseRecorder) {
+			defer wg.Done()
+			c.do(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4&model=dl360", nil), serve)
+		}(recorders[i])
+	}
+
+	// wait for the other requests to join the first one before it finishes
+	deadline := time.Now().Add(5 * time.Second)
+	for testutil.ToFloat64(c.coalesced.WithLabelValues("/scrape")) != requests-1 {
+		if time.Now().After(deadline) {
+			t.Fatal("requests were not coalesced")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("scrapes = %d, want 1", got)
+	}
+	for _, rr := range recorders {
+		if rr.Code != http.StatusOK || rr.Body.String() != "redfish_up 1\n" || rr.Header().Get("Content-Type") != "text/plain" {
+			t.Fatalf("status = %d body = %q, want the shared response", rr.Code, rr.Body.String())
+		}
+	}
+
+	// a later request scrapes again
+	c.do(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4&model=dl360", nil), serve)
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("scrapes = %d, want 2", got)
+	}
+}
+
+func Test_Coalescer_LeaderGoesAway(t *testing.T) {
+	c := NewCoalescer(prometheus.NewRegistry())
+
+	release := make(chan struct{})
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		if err := r.Context().Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("redfish_up 1\n"))
+	}
+
+	// the first request goes away while its scrape runs
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan struct{})
+	go func() {
+		defer close(leader)
+		c.do(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil).WithContext(ctx), serve)
+	}()
+
+	follower := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.do(follower, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil), serve)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for testutil.ToFloat64(c.coalesced.WithLabelValues("/scrape")) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("requests were not coalesced")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-leader:
+	case <-time.After(5 * time.Second):
+		t.Fatal("first request still waiting after its context was canceled")
+	}
+
+	close(release)
+	<-done
+	if follower.Code != http.StatusOK || follower.Body.String() != "redfish_up 1\n" {
+		t.Fatalf("status = %d body = %q, want the shared response", follower.Code, follower.Body.String())
+	}
+}
+
+func Test_scrapeTimeout(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil)
+	r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "9.5")
+	if got := scrapeTimeout(r); got != 9500*time.Millisecond {
+		t.Fatalf("scrapeTimeout() = %s, want 9.5s", got)
+	}
+}
+
+func Test_Limiter_QueuesAndSheds(t *testing.T) {
+	l := NewLimiter(1, 2, 1, 200*time.Millisecond, prometheus.NewRegistry())
+
+	release := make(chan struct{})
+	running := make(chan struct{})
+	go l.do(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil), func() {
+		close(running)
+		<-release
+	})
+	<-running
+
+	// the second scrape of the target waits in the queue
+	queued := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rr := httptest.NewRecorder()
+		l.do(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=5.6.7.8", nil), func() {})
+		queued <- rr
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for testutil.ToFloat64(l.queueDepth) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("scrape was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the queue is full
+	rr := httptest.NewRecorder()
+	l.do(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=9.9.9.9", nil), func() { t.Fatal("scrape over the queue ran") })
+	if rr.Code != http.StatusServiceUnavailable || rr.Header().Get("Retry-After") != "1" {
+		t.Fatalf("status = %d Retry-After = %q, want 503 and 1", rr.Code, rr.Header().Get("Retry-After"))
+	}
+
+	// the queued scrape times out
+	if rr := <-queued; rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("queued status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+
+	// the target is over its own cap while its first scrape runs and the second one waits
+	l.MaxQueue = 2
+	go l.do(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil), func() {})
+	for testutil.ToFloat64(l.queueDepth) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("scrape was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	rr = httptest.NewRecorder()
+	l.do(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4", nil), func() { t.Fatal("scrape over the target cap ran") })
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Fatalf("status = %d Retry-After = %q, want 429 with Retry-After", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	close(release)
+
+	for reason, want := range map[string]float64{"queue_full": 1, "queue_timeout": 1, "target_limit": 1} {
+		if got := testutil.ToFloat64(l.rejected.WithLabelValues("/scrape", reason)); got != want {
+			t.Fatalf("rejected %s = %v, want %v", reason, got, want)
+		}
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	modules := `
+modules:
+  dell_r750:
+    model: dell
+    credentialProfile: profile1
+    components: [thermal, power, drives]
+    excludes:
+      drives: "^NVMe"
+    timeout: 20s
+    concurrency: 2
+    tls:
+      insecureSkipVerify: true
+    auth: session
+    plugins: [nuova]
+  default:
+metrics:
+  - path: /redfish/v1/Chassis/*/Sensors/*
+    name: redfish_sensor_health
+    help: sensor health 1 = OK, 0 = Critical
+    value: $.Status.Health
+    labels:
+      sensor: $.Id
+    valueMap:
+      OK: 1
+      Critical: 0
+`
+	if err := os.WriteFile(path, []byte(modules), 0600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cf, err := LoadConfigFile(path, map[string]interface{}{"firmware": regexp.MustCompile("^BIOS")})
+	if err != nil {
+		t.Fatalf("LoadConfigFile error: %v", err)
+	}
+	got := cf.Modules
+	m, ok := got["dell_r750"]
+	if !ok || m.Model != "dell" || m.Timeout != 20*time.Second || m.Concurrency != 2 || m.Auth != authSession ||
+		m.TLS.InsecureSkipVerify == nil || !*m.TLS.InsecureSkipVerify {
+		t.Fatalf("module = %+v", m)
+	}
+	if _, ok := m.excludes["drive"]; !ok {
+		t.Fatalf("excludes = %v, want the drive excludes of the module", m.excludes)
+	}
+	if _, ok := m.excludes["firmware"]; !ok {
+		t.Fatalf("excludes = %v, want the global firmware excludes", m.excludes)
+	}
+	if _, ok := got["default"]; !ok {
+		t.Fatal("empty module was not loaded")
+	}
+	if len(cf.Metrics) != 1 || cf.Metrics[0].ValueMap["OK"] != 1 {
+		t.Fatalf("metrics = %+v", cf.Metrics)
+	}
+
+	for _, invalid := range []string{
+		"modules:\n  m:\n    components: [fans]\n",
+		"modules:\n  m:\n    excludes:\n      memory: x\n",
+		"modules:\n  m:\n    excludes:\n      drives: \"(\"\n",
+		"modules:\n  m:\n    auth: digest\n",
+		"modules:\n  m:\n    plugins: [unknown]\n",
+		"modules:\n  m:\n    tls:\n      minVersion: \"1.4\"\n",
+		"targetTLS:\n  - targets: \"(\"\n",
+		"metrics:\n  - {path: /redfish/v1/Chassis/*, name: \"redfish-x\", help: x, value: $.x}\n",
+		"metrics:\n  - {path: /redfish/v1/Chassis/*, name: redfish_x, help: x, value: x}\n",
+		"metrics:\n  - {path: /redfish/v1/Chassis/*, name: redfish_x, help: x, value: $.x, type: histogram}\n",
+		"metrics:\n  - {path: /redfish/v1/Chassis/*, name: redfish_x, value: $.x}\n",
+		"metrics:\n  - {path: /redfish/v1/Chassis/*, name: redfish_x, help: x, value: $.x, labels: {chassisModel: $.Model}}\n",
+		"metrics:\n  - {path: /redfish/v1/Chassis/*, name: redfish_x, help: x, value: $.x}\n  - {path: /redfish/v1/Systems/*, name: redfish_x, help: x, value: $.x}\n",
+	} {
+		if err := os.WriteFile(path, []byte(invalid), 0600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := LoadConfigFile(path, nil); err == nil {
+			t.Fatalf("LoadConfigFile returned nil error for %q", invalid)
+		}
+	}
+}
+
+func Test_Module_Apply(t *testing.T) {
+	m := &Module{
+		Model:             "dell",
+		CredentialProfile: "profile1",
+		Components:        []string{"thermal", "power"},
+		excludes:          map[string]interface{}{"drive": regexp.MustCompile("^NVMe")},
+	}
+	cfg := &ScrapeConfig{Modules: map[string]*Module{"dell_r750": m}}
+
+	r, got := m.apply(httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4&module=dell_r750&model=idrac", nil), cfg)
+	query := r.URL.Query()
+	if query.Get("model") != "idrac" || query.Get("credential_profile") != "profile1" || query.Get("components") != "thermal,power" {
+		t.Fatalf("query = %v, want the module settings under the request parameters", query)
+	}
+	if got == cfg || !reflect.DeepEqual(got.Excludes, m.excludes) || cfg.Excludes != nil {
+		t.Fatalf("excludes = %v, want the module excludes on a copy of the config", got.Excludes)
+	}
+	if !m.partial() {
+		t.Fatal("module with components is not a partial scrape")
+	}
+
+	rr := httptest.NewRecorder()
+	ScrapeHandler(cfg)(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4&module=unknown", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 for an unknown module", rr.Code)
+	}
+}
+
+func Test_CurrentConfig_Swap(t *testing.T) {
+	script := writeCredentialsScript(t, "exit 42\n")
+	current := NewCurrentConfig(&ScrapeConfig{CredentialsScript: script})
+	h := current.Handler(ScrapeHandler)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4&module=dell_r750", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 before the module is loaded", rr.Code)
+	}
+
+	cfg := *current.Load()
+	cfg.Modules = map[string]*Module{"dell_r750": {Model: "dell"}}
+	current.Store(&cfg)
+
+	rr = httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/scrape?target=1.2.3.4&module=dell_r750", nil))
+	if rr.Code != http.StatusInternalServerError || !strings.Contains(rr.Body.String(), credentialsRetrievalFailure) {
+		t.Fatalf("status = %d body = %q, want the scrape to run with the swapped config", rr.Code, rr.Body.String())
+	}
+}
+
+func Test_ScrapeHandlers_UnknownPlugin(t *testing.T) {
+	cfg := &ScrapeConfig{}
+
+	for path, h := range map[string]http.HandlerFunc{
+		"/scrape":         ScrapeHandler(cfg),
+		"/scrape/partial": PartialScrapeHandler(cfg),
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		q := url.Values{}
+		q.Set("target", "1.2.3.4")
+		q.Set("components", "thermal")
+		q.Set("plugins", "nuova,unknown")
+		req.URL.RawQuery = q.Encode()
+
+		rr := httptest.NewRecorder()
+		h(rr, req)
+
+		if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "unknown plugin unknown") {
+			t.Fatalf("%s status = %d body = %q, want %d for the unknown plugin", path, rr.Code, rr.Body.String(), http.StatusBadRequest)
+		}
+	}
+}
+
+func Test_PluginsHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	PluginsHandler(rr, httptest.NewRequest(http.MethodGet, "/plugins", nil))
+
+	var plugins []struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Models      []string `json:"models"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &plugins); err != nil {
+		t.Fatalf("unmarshal plugins: %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Name != "nuova" || plugins[0].Description == "" || len(plugins[0].Models) == 0 {
+		t.Fatalf("plugins = %+v, want the nuova plugin", plugins)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	upMetricName          = "redfish_up"
+	upMetricHelp          = "was the last scrape of fishymetrics successful."
+	lastSuccessMetricName = "redfish_last_success_timestamp_seconds"
+	lastSuccessMetricHelp = "Unix time of the last successful scrape of the target, the metrics of that scrape are served while the target is unreachable"
+)
+
+// StaleCache keeps the metrics of the last successful scrape of every target for a window, a failed scrape
+// within the window serves them with the redfish_up of the failure so the hardware series don't go stale
+// while a BMC is temporarily unreachable
+type StaleCache struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	scrapes map[string]*staleScrape
+}
+
+type staleScrape struct {
+	families []*dto.MetricFamily
+	success  time.Time
+}
+
+// NewStaleCache returns a StaleCache that serves the last successful metrics for window
+func NewStaleCache(window time.Duration) *StaleCache {
+	return &StaleCache{
+		window:  window,
+		scrapes: make(map[string]*staleScrape),
+	}
+}
+
+// staleKey identifies a scrape by its route and query parameters, partial scrapes of different components
+// are kept apart
+func staleKey(path string, query url.Values) string {
+	return path + "?" + query.Encode()
+}
+
+// gatherer wraps the gatherer of a scrape, successful scrapes are stored and failed ones are replaced
+// by the last successful metrics when there are some within the window
+func (c *StaleCache) gatherer(key string, g prometheus.Gatherer) prometheus.Gatherer {
+	if c == nil {
+		return g
+	}
+
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		families, err := g.Gather()
+		if err != nil {
+			return families, err
+		}
+
+		up, ok := gaugeValue(families, upMetricName)
+		if ok && up == 1 {
+			now := time.Now()
+			// the stored families are copied since the returned slice is appended to and sorted
+			c.store(key, append([]*dto.MetricFamily(nil), families...), now)
+			return sortFamilies(append(families, gaugeFamily(lastSuccessMetricName, lastSuccessMetricHelp, float64(now.Unix())))), nil
+		}
+
+		if stale, ok := c.stale(key, up); ok {
+			return stale, nil
+		}
+		return families, nil
+	})
+}
+
+// serveStale serves the last successful metrics of key with redfish_up 0 when the exporter could not be created,
+// it returns false when there are none within the window
+func (c *StaleCache) serveStale(w http.ResponseWriter, r *http.Request, key string) bool {
+	if c == nil {
+		return false
+	}
+
+	families, ok := c.stale(key, 0)
+	if !ok {
+		return false
+	}
+
+	g := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		return families, nil
+	})
+	promhttp.HandlerFor(g, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	return true
+}
+
+func (c *StaleCache) store(key string, families []*dto.MetricFamily, success time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// drop the targets that are no longer scraped
+	for k, s := range c.scrapes {
+		if time.Since(s.success) > c.window {
+			delete(c.scrapes, k)
+		}
+	}
+
+	c.scrapes[key] = &staleScrape{
+		families: families,
+		success:  success,
+	}
+}
+
+// stale returns the last successful metrics of key with redfish_up set to up
+func (c *StaleCache) stale(key string, up float64) ([]*dto.MetricFamily, bool) {
+	c.mu.Lock()
+	s, ok := c.scrapes[key]
+	if ok && time.Since(s.success) > c.window {
+		delete(c.scrapes, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	families := make([]*dto.MetricFamily, 0, len(s.families)+2)
+	for _, f := range s.families {
+		if f.GetName() == upMetricName {
+			continue
+		}
+		families = append(families, f)
+	}
+	families = append(families,
+		gaugeFamily(upMetricName, upMetricHelp, up),
+		gaugeFamily(lastSuccessMetricName, lastSuccessMetricHelp, float64(s.success.Unix())))
+
+	return sortFamilies(families), true
+}
+
+// gaugeValue returns the value of the unlabelled gauge name
+func gaugeValue(families []*dto.MetricFamily, name string) (float64, bool) {
+	for _, f := range families {
+		if f.GetName() == name && len(f.GetMetric()) == 1 {
+			return f.GetMetric()[0].GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+func gaugeFamily(name, help string, value float64) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name: &name,
+		Help: &help,
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{
+			{Gauge: &dto.Gauge{Value: &value}},
+		},
+	}
+}
+
+func sortFamilies(families []*dto.MetricFamily) []*dto.MetricFamily {
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+	return families
+}
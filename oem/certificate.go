@@ -0,0 +1,58 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/CertificateService/
+
+// CertificateService is the top level json object for the CertificateService
+type CertificateService struct {
+	CertificateLocations Link `json:"CertificateLocations"`
+}
+
+// /redfish/v1/CertificateService/CertificateLocations/
+
+// CertificateLocations contains the links to every certificate installed on the BMC
+type CertificateLocations struct {
+	Links struct {
+		Certificates []Link `json:"Certificates"`
+	} `json:"Links"`
+}
+
+// Certificate is the json object for a certificate resource
+type Certificate struct {
+	CertificateString        string                `json:"CertificateString,omitempty"`
+	CertificateType          string                `json:"CertificateType,omitempty"`
+	CertificateUsageTypes    []string              `json:"CertificateUsageTypes,omitempty"`
+	Fingerprint              string                `json:"Fingerprint,omitempty"`
+	FingerprintHashAlgorithm string                `json:"FingerprintHashAlgorithm,omitempty"`
+	Issuer                   CertificateIdentifier `json:"Issuer,omitempty"`
+	SerialNumber             string                `json:"SerialNumber,omitempty"`
+	Subject                  CertificateIdentifier `json:"Subject,omitempty"`
+	ValidNotAfter            string                `json:"ValidNotAfter,omitempty"`
+	ValidNotBefore           string                `json:"ValidNotBefore,omitempty"`
+	Url                      string                `json:"@odata.id"`
+}
+
+// CertificateIdentifier contains the subject or issuer of a certificate
+type CertificateIdentifier struct {
+	City               string `json:"City,omitempty"`
+	CommonName         string `json:"CommonName,omitempty"`
+	Country            string `json:"Country,omitempty"`
+	Organization       string `json:"Organization,omitempty"`
+	OrganizationalUnit string `json:"OrganizationalUnit,omitempty"`
+	State              string `json:"State,omitempty"`
+}
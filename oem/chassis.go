@@ -37,10 +37,13 @@ type Status struct {
 
 // /redfish/v1/Chassis/XXXXX
 type Chassis struct {
-	Links      ChassisLinks `json:"Links"`
-	LinksLower ChassisLinks `json:"links"`
-	PowerAlt   Link         `json:"Power"`
-	ThermalAlt Link         `json:"Thermal"`
+	EnvironmentMetrics Link         `json:"EnvironmentMetrics"`
+	Links              ChassisLinks `json:"Links"`
+	LinksLower         ChassisLinks `json:"links"`
+	PowerAlt           Link         `json:"Power"`
+	PowerSubsystem     Link         `json:"PowerSubsystem"`
+	ThermalAlt         Link         `json:"Thermal"`
+	ThermalSubsystem   Link         `json:"ThermalSubsystem"`
 }
 
 type ChassisLinks struct {
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/Chassis/X/EnvironmentMetrics/
+// /redfish/v1/Chassis/X/PowerSubsystem/PowerSupplies/X/Metrics/
+
+// EnvironmentMetrics is the top level json object for energy readings, the same struct is
+// used for the PowerSupplyMetrics resource since both expose an EnergykWh excerpt
+type EnvironmentMetrics struct {
+	ID           string        `json:"Id"`
+	Name         string        `json:"Name"`
+	EnergyJoules SensorReading `json:"EnergyJoules,omitempty"`
+	EnergykWh    SensorReading `json:"EnergykWh,omitempty"`
+	EnergySensor SensorReading `json:"EnergySensor,omitempty"`
+	Url          string        `json:"@odata.id"`
+}
+
+// SensorReading is the json object for a Sensor excerpt
+type SensorReading struct {
+	DataSourceUri   string      `json:"DataSourceUri,omitempty"`
+	Reading         interface{} `json:"Reading"`
+	SensorResetTime string      `json:"SensorResetTime,omitempty"`
+}
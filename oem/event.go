@@ -0,0 +1,59 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/EventService/
+
+// EventService is the json object for the EventService resource
+type EventService struct {
+	ServiceEnabled     *bool  `json:"ServiceEnabled,omitempty"`
+	ServerSentEventUri string `json:"ServerSentEventUri,omitempty"`
+	Subscriptions      Link   `json:"Subscriptions"`
+	Url                string `json:"@odata.id"`
+}
+
+// /redfish/v1/EventService/Subscriptions/X/
+
+// EventDestination is the json object for an EventService subscription
+type EventDestination struct {
+	ID          string `json:"Id,omitempty"`
+	Destination string `json:"Destination"`
+	Context     string `json:"Context,omitempty"`
+	Protocol    string `json:"Protocol,omitempty"`
+	Url         string `json:"@odata.id,omitempty"`
+}
+
+// Event is the json object a BMC pushes to an event subscription destination
+type Event struct {
+	ID      string        `json:"Id,omitempty"`
+	Name    string        `json:"Name,omitempty"`
+	Context string        `json:"Context,omitempty"`
+	Events  []EventRecord `json:"Events"`
+}
+
+// EventRecord is a single record of an Event
+type EventRecord struct {
+	EventID           string `json:"EventId,omitempty"`
+	EventType         string `json:"EventType,omitempty"`
+	EventTimestamp    string `json:"EventTimestamp,omitempty"`
+	Context           string `json:"Context,omitempty"`
+	Message           string `json:"Message,omitempty"`
+	MessageID         string `json:"MessageId,omitempty"`
+	MessageSeverity   string `json:"MessageSeverity,omitempty"`
+	Severity          string `json:"Severity,omitempty"`
+	OriginOfCondition Link   `json:"OriginOfCondition,omitempty"`
+}
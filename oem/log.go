@@ -0,0 +1,52 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/Managers/XX/LogServices/X/
+// /redfish/v1/Systems/XXXXX/LogServices/X/
+
+// LogService is the json object for a log service such as the SEL or HPE IML
+type LogService struct {
+	ID      string `json:"Id"`
+	Name    string `json:"Name"`
+	Entries Link   `json:"Entries"`
+	Url     string `json:"@odata.id"`
+}
+
+// /redfish/v1/Systems/XXXXX/LogServices/X/Entries/
+
+// LogEntryCollection is the json object for a page of log entries
+type LogEntryCollection struct {
+	Members      []LogEntry `json:"Members"`
+	MembersCount int        `json:"Members@odata.count"`
+	NextLink     string     `json:"Members@odata.nextLink,omitempty"`
+	Url          string     `json:"@odata.id"`
+}
+
+// LogEntry is the json object for a single log entry
+type LogEntry struct {
+	ID              string `json:"Id"`
+	Name            string `json:"Name,omitempty"`
+	Created         string `json:"Created,omitempty"`
+	EntryType       string `json:"EntryType,omitempty"`
+	Message         string `json:"Message,omitempty"`
+	MessageID       string `json:"MessageId,omitempty"`
+	MessageSeverity string `json:"MessageSeverity,omitempty"`
+	SensorType      string `json:"SensorType,omitempty"`
+	Severity        string `json:"Severity,omitempty"`
+	Url             string `json:"@odata.id,omitempty"`
+}
@@ -31,8 +31,15 @@ type Manager struct {
 	LinksLower struct {
 		ManagerForServers ServerManagerURLWrapper `json:"ManagerForServers"`
 	} `json:"links"`
-	Model       string `json:"Model"`
-	Description string `json:"Description"`
+	Model                 string `json:"Model"`
+	Description           string `json:"Description"`
+	DateTime              string `json:"DateTime,omitempty"`
+	LastResetTime         string `json:"LastResetTime,omitempty"`
+	ManagerDiagnosticData Link   `json:"ManagerDiagnosticData,omitempty"`
+	NetworkProtocol       Link   `json:"NetworkProtocol,omitempty"`
+	LogServices           Link   `json:"LogServices,omitempty"`
+	Status                Status `json:"Status,omitempty"`
+	Url                   string `json:"@odata.id"`
 }
 
 type ServerManagerURL struct {
@@ -77,6 +84,31 @@ func (w *ServerManagerURLWrapper) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// /redfish/v1/Managers/XX/ManagerDiagnosticData/
+
+// ManagerDiagnosticData contains the BMC resource usage statistics
+type ManagerDiagnosticData struct {
+	FreeStorageSpaceKiB      interface{}         `json:"FreeStorageSpaceKiB,omitempty"`
+	MemoryStatistics         MemoryStatistics    `json:"MemoryStatistics,omitempty"`
+	ProcessorStatistics      ProcessorStatistics `json:"ProcessorStatistics,omitempty"`
+	ServiceRootUptimeSeconds interface{}         `json:"ServiceRootUptimeSeconds,omitempty"`
+	Url                      string              `json:"@odata.id"`
+}
+
+// MemoryStatistics contains the BMC memory usage in bytes
+type MemoryStatistics struct {
+	AvailableBytes interface{} `json:"AvailableBytes,omitempty"`
+	FreeBytes      interface{} `json:"FreeBytes,omitempty"`
+	TotalBytes     interface{} `json:"TotalBytes,omitempty"`
+	UsedBytes      interface{} `json:"UsedBytes,omitempty"`
+}
+
+// ProcessorStatistics contains the BMC processor usage in percent
+type ProcessorStatistics struct {
+	KernelPercent interface{} `json:"KernelPercent,omitempty"`
+	UserPercent   interface{} `json:"UserPercent,omitempty"`
+}
+
 type IloSelfTest struct {
 	Name   string `json:"SelfTestName"`
 	Status string `json:"Status"`
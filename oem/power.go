@@ -28,6 +28,7 @@ type PowerMetrics struct {
 	Name          string              `json:"Name"`
 	PowerControl  PowerControlWrapper `json:"PowerControl"`
 	PowerSupplies []PowerSupply       `json:"PowerSupplies,omitempty"`
+	Redundancy    []Redundancy        `json:"Redundancy,omitempty"`
 	Voltages      []Voltages          `json:"Voltages,omitempty"`
 	Url           string              `json:"@odata.id"`
 }
@@ -37,9 +38,17 @@ type PowerControl struct {
 	MemberID           string              `json:"MemberId"`
 	PowerCapacityWatts interface{}         `json:"PowerCapacityWatts,omitempty"`
 	PowerConsumedWatts interface{}         `json:"PowerConsumedWatts"`
+	PowerLimit         PowerLimit          `json:"PowerLimit,omitempty"`
 	PowerMetrics       PowerMetricsWrapper `json:"PowerMetrics"`
 }
 
+// PowerLimit contains the configured power cap and the action taken when it is exceeded
+type PowerLimit struct {
+	CorrectionInMs interface{} `json:"CorrectionInMs,omitempty"`
+	LimitException string      `json:"LimitException,omitempty"`
+	LimitInWatts   interface{} `json:"LimitInWatts,omitempty"`
+}
+
 type PowerControlSlice struct {
 	PowerControl []PowerControl
 }
@@ -108,12 +117,14 @@ type PowerSupply struct {
 	Model                string       `json:"Model"`
 	Name                 string       `json:"Name"`
 	Oem                  OemPower     `json:"Oem,omitempty"`
-	PowerCapacityWatts   int          `json:"PowerCapacityWatts,omitempty"`
+	PowerCapacityWatts   interface{}  `json:"PowerCapacityWatts,omitempty"`
+	EfficiencyPercent    interface{}  `json:"EfficiencyPercent,omitempty"`
 	PowerInputWatts      interface{}  `json:"PowerInputWatts,omitempty"`
 	PowerSupplyType      string       `json:"PowerSupplyType"`
 	SerialNumber         string       `json:"SerialNumber"`
 	SparePartNumber      string       `json:"SparePartNumber"`
 	Status               Status       `json:"Status"`
+	Url                  string       `json:"@odata.id,omitempty"`
 }
 
 // InputRange is the top level json object for input voltage metadata
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/Chassis/X/PowerSubsystem/
+
+// PowerSubsystem is the top level json object for the newer PowerSubsystem metadata
+type PowerSubsystem struct {
+	ID                    string           `json:"Id"`
+	Name                  string           `json:"Name"`
+	Allocation            PowerAllocation  `json:"Allocation,omitempty"`
+	CapacityWatts         interface{}      `json:"CapacityWatts,omitempty"`
+	PowerSupplies         Link             `json:"PowerSupplies"`
+	PowerSupplyRedundancy []RedundantGroup `json:"PowerSupplyRedundancy,omitempty"`
+	Status                Status           `json:"Status,omitempty"`
+	Url                   string           `json:"@odata.id"`
+}
+
+// PowerAllocation contains the requested and allocated power for the subsystem
+type PowerAllocation struct {
+	AllocatedWatts interface{} `json:"AllocatedWatts,omitempty"`
+	RequestedWatts interface{} `json:"RequestedWatts,omitempty"`
+}
+
+// /redfish/v1/Chassis/X/PowerSubsystem/PowerSupplies/X/
+
+// PowerSupplyUnit is the json object for a power supply member of the PowerSubsystem
+type PowerSupplyUnit struct {
+	ID                 string             `json:"Id"`
+	Name               string             `json:"Name"`
+	EfficiencyRatings  []EfficiencyRating `json:"EfficiencyRatings,omitempty"`
+	FirmwareVersion    string             `json:"FirmwareVersion"`
+	Location           PhysicalLocation   `json:"Location,omitempty"`
+	Manufacturer       string             `json:"Manufacturer"`
+	Metrics            Link               `json:"Metrics,omitempty"`
+	Model              string             `json:"Model"`
+	PowerCapacityWatts interface{}        `json:"PowerCapacityWatts,omitempty"`
+	PowerSupplyType    string             `json:"PowerSupplyType"`
+	SerialNumber       string             `json:"SerialNumber"`
+	Status             Status             `json:"Status"`
+	Url                string             `json:"@odata.id"`
+}
+
+// EfficiencyRating contains the rated efficiency of a power supply at a given load
+type EfficiencyRating struct {
+	EfficiencyPercent interface{} `json:"EfficiencyPercent,omitempty"`
+	LoadPercent       interface{} `json:"LoadPercent,omitempty"`
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// Redundancy is the json object for a legacy redundancy group found in the Power and Thermal resources
+type Redundancy struct {
+	MemberID        interface{} `json:"MemberId"`
+	Name            string      `json:"Name"`
+	Mode            string      `json:"Mode"`
+	MaxNumSupported interface{} `json:"MaxNumSupported,omitempty"`
+	MinNumNeeded    interface{} `json:"MinNumNeeded,omitempty"`
+	RedundancySet   []Link      `json:"RedundancySet"`
+	Status          Status      `json:"Status"`
+	Url             string      `json:"@odata.id"`
+}
+
+// RedundantGroup is the json object for a redundancy group found in the PowerSubsystem and ThermalSubsystem resources
+type RedundantGroup struct {
+	MaxSupportedInGroup interface{} `json:"MaxSupportedInGroup,omitempty"`
+	MinNeededInGroup    interface{} `json:"MinNeededInGroup,omitempty"`
+	RedundancyGroup     []Link      `json:"RedundancyGroup"`
+	RedundancyType      string      `json:"RedundancyType"`
+	Status              Status      `json:"Status"`
+}
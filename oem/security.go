@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/Managers/XX/NetworkProtocol/
+
+// NetworkProtocol is the top level json object for the manager network services
+type NetworkProtocol struct {
+	HTTP         Protocol `json:"HTTP,omitempty"`
+	HTTPS        Protocol `json:"HTTPS,omitempty"`
+	IPMI         Protocol `json:"IPMI,omitempty"`
+	KVMIP        Protocol `json:"KVMIP,omitempty"`
+	SNMP         Protocol `json:"SNMP,omitempty"`
+	SSH          Protocol `json:"SSH,omitempty"`
+	Telnet       Protocol `json:"Telnet,omitempty"`
+	VirtualMedia Protocol `json:"VirtualMedia,omitempty"`
+	Url          string   `json:"@odata.id"`
+}
+
+// Protocol contains the enabled state and port of a network service
+type Protocol struct {
+	Certificates    Link        `json:"Certificates,omitempty"`
+	Port            interface{} `json:"Port,omitempty"`
+	ProtocolEnabled *bool       `json:"ProtocolEnabled,omitempty"`
+}
+
+// /redfish/v1/AccountService/
+
+// AccountService is the top level json object for the account and lockout policy
+type AccountService struct {
+	AccountLockoutDuration  interface{} `json:"AccountLockoutDuration,omitempty"`
+	AccountLockoutThreshold interface{} `json:"AccountLockoutThreshold,omitempty"`
+	Accounts                Link        `json:"Accounts"`
+	MinPasswordLength       interface{} `json:"MinPasswordLength,omitempty"`
+	ServiceEnabled          *bool       `json:"ServiceEnabled,omitempty"`
+	Url                     string      `json:"@odata.id"`
+}
+
+// /redfish/v1/AccountService/Accounts/X/
+
+// ManagerAccount is the json object for a BMC user account
+type ManagerAccount struct {
+	Enabled  bool   `json:"Enabled"`
+	Locked   bool   `json:"Locked"`
+	RoleID   string `json:"RoleId"`
+	UserName string `json:"UserName"`
+	Url      string `json:"@odata.id"`
+}
+
+// /redfish/v1/Systems/XXXXX/SecureBoot/
+
+// SecureBoot is the top level json object for the UEFI Secure Boot state
+type SecureBoot struct {
+	SecureBootCurrentBoot string `json:"SecureBootCurrentBoot,omitempty"`
+	SecureBootEnable      *bool  `json:"SecureBootEnable,omitempty"`
+	SecureBootMode        string `json:"SecureBootMode,omitempty"`
+	Url                   string `json:"@odata.id"`
+}
+
+// TrustedModule is the json object for a TPM listed in the system resource
+type TrustedModule struct {
+	FirmwareVersion string `json:"FirmwareVersion,omitempty"`
+	InterfaceType   string `json:"InterfaceType,omitempty"`
+	Status          Status `json:"Status"`
+}
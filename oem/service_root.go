@@ -0,0 +1,48 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+import (
+	"encoding/json"
+)
+
+// /redfish/v1/
+
+// ServiceRoot contains the vendor of the BMC and the optional features of its Redfish service
+type ServiceRoot struct {
+	Vendor  string `json:"Vendor,omitempty"`
+	Product string `json:"Product,omitempty"`
+	// Oem is keyed by the vendor namespace, only the keys are used to identify the vendor
+	Oem                       map[string]json.RawMessage `json:"Oem,omitempty"`
+	ProtocolFeaturesSupported ProtocolFeaturesSupported  `json:"ProtocolFeaturesSupported,omitempty"`
+}
+
+// ProtocolFeaturesSupported contains the optional query parameters the service supports
+type ProtocolFeaturesSupported struct {
+	ExpandQuery  ExpandQuery `json:"ExpandQuery,omitempty"`
+	SelectQuery  bool        `json:"SelectQuery,omitempty"`
+	TopSkipQuery bool        `json:"TopSkipQuery,omitempty"`
+}
+
+// ExpandQuery contains the $expand options the service supports
+type ExpandQuery struct {
+	ExpandAll bool `json:"ExpandAll,omitempty"`
+	Levels    bool `json:"Levels,omitempty"`
+	Links     bool `json:"Links,omitempty"`
+	NoLinks   bool `json:"NoLinks,omitempty"`
+	MaxLevels int  `json:"MaxLevels,omitempty"`
+}
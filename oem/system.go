@@ -21,16 +21,35 @@ package oem
 // ServerManager contains the BIOS version and Serial number of the chassis,
 // we will also collect memory summary and storage battery metrics if present
 type System struct {
-	BiosVersion       string        `json:"BiosVersion"`
-	SerialNumber      string        `json:"SerialNumber"`
-	SystemHostname    string        `json:"HostName"`
-	Oem               OemSys        `json:"Oem"`
-	MemorySummary     MemorySummary `json:"MemorySummary"`
-	Memory            Link          `json:"Memory"`
-	Volumes           LinksWrapper  `json:"Volumes"`
-	FirmwareInventory LinksWrapper  `json:"FirmwareInventory"`
-	Storage           Link          `json:"Storage"`
-	UpdateService     Link          `json:"UpdateService"`
+	BiosVersion             string          `json:"BiosVersion"`
+	SerialNumber            string          `json:"SerialNumber"`
+	SystemHostname          string          `json:"HostName"`
+	Oem                     OemSys          `json:"Oem"`
+	MemorySummary           MemorySummary   `json:"MemorySummary"`
+	Memory                  Link            `json:"Memory"`
+	Volumes                 LinksWrapper    `json:"Volumes"`
+	FirmwareInventory       LinksWrapper    `json:"FirmwareInventory"`
+	Storage                 Link            `json:"Storage"`
+	UpdateService           Link            `json:"UpdateService"`
+	EnvironmentMetrics      Link            `json:"EnvironmentMetrics"`
+	PowerState              string          `json:"PowerState,omitempty"`
+	BootProgress            BootProgress    `json:"BootProgress,omitempty"`
+	IndicatorLED            string          `json:"IndicatorLED,omitempty"`
+	LocationIndicatorActive *bool           `json:"LocationIndicatorActive,omitempty"`
+	Status                  Status          `json:"Status,omitempty"`
+	SecureBoot              Link            `json:"SecureBoot,omitempty"`
+	TrustedModules          []TrustedModule `json:"TrustedModules,omitempty"`
+	AccountService          Link            `json:"AccountService,omitempty"`
+	CertificateService      Link            `json:"CertificateService,omitempty"`
+	TelemetryService        Link            `json:"TelemetryService,omitempty"`
+	LogServices             Link            `json:"LogServices,omitempty"`
+}
+
+// BootProgress contains the last boot progress state reported by the host
+type BootProgress struct {
+	LastState     string `json:"LastState,omitempty"`
+	OemLastState  string `json:"OemLastState,omitempty"`
+	LastStateTime string `json:"LastStateTime,omitempty"`
 }
 
 type OemSys struct {
@@ -44,6 +63,7 @@ type HpeSys struct {
 	IloSelfTest []IloSelfTest         `json:"iLOSelfTestResults"`
 	Links       SystemLinksUpper      `json:"Links"`
 	LinksLower  SystemLinksLower      `json:"links"`
+	PostState   string                `json:"PostState,omitempty"`
 }
 
 type SystemLinksUpper struct {
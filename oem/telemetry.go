@@ -0,0 +1,59 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/TelemetryService/
+
+// TelemetryService is the json object for the TelemetryService resource
+type TelemetryService struct {
+	MetricReportDefinitions Link   `json:"MetricReportDefinitions"`
+	MetricReports           Link   `json:"MetricReports"`
+	ServiceEnabled          *bool  `json:"ServiceEnabled,omitempty"`
+	Status                  Status `json:"Status"`
+	Url                     string `json:"@odata.id"`
+}
+
+// /redfish/v1/TelemetryService/MetricReportDefinitions/X/
+
+// MetricReportDefinition is the json object for a MetricReportDefinition
+type MetricReportDefinition struct {
+	ID                            string `json:"Id"`
+	MetricReport                  Link   `json:"MetricReport"`
+	MetricReportDefinitionEnabled *bool  `json:"MetricReportDefinitionEnabled,omitempty"`
+	Status                        Status `json:"Status"`
+	Url                           string `json:"@odata.id"`
+}
+
+// /redfish/v1/TelemetryService/MetricReports/X/
+
+// MetricReport is the json object for a MetricReport
+type MetricReport struct {
+	ID           string        `json:"Id"`
+	Name         string        `json:"Name"`
+	Timestamp    string        `json:"Timestamp,omitempty"`
+	MetricValues []MetricValue `json:"MetricValues"`
+	Url          string        `json:"@odata.id"`
+}
+
+// MetricValue is a single reading of a MetricReport, MetricValue is a string in the schema but some
+// BMCs report it as a number
+type MetricValue struct {
+	MetricID       string      `json:"MetricId"`
+	MetricProperty string      `json:"MetricProperty,omitempty"`
+	MetricValue    interface{} `json:"MetricValue"`
+	Timestamp      string      `json:"Timestamp,omitempty"`
+}
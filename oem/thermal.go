@@ -23,6 +23,7 @@ type ThermalMetrics struct {
 	ID           string        `json:"Id"`
 	Fans         []Fan         `json:"Fans"`
 	Name         string        `json:"Name"`
+	Redundancy   []Redundancy  `json:"Redundancy,omitempty"`
 	Status       Status        `json:"Status,omitempty"`
 	Temperatures []Temperature `json:"Temperatures"`
 	Url          string        `json:"@odata.id"`
@@ -37,6 +38,7 @@ type Fan struct {
 	CurrentReading int         `json:"CurrentReading"`
 	ReadingUnits   string      `json:"ReadingUnits"`
 	Status         Status      `json:"Status"`
+	Url            string      `json:"@odata.id,omitempty"`
 }
 
 // Temperature is the json object for a temperature sensor module
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2026 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oem
+
+// /redfish/v1/Chassis/X/ThermalSubsystem/
+
+// ThermalSubsystem is the top level json object for the newer ThermalSubsystem metadata
+type ThermalSubsystem struct {
+	ID            string           `json:"Id"`
+	Name          string           `json:"Name"`
+	FanRedundancy []RedundantGroup `json:"FanRedundancy,omitempty"`
+	Fans          Link             `json:"Fans"`
+	Status        Status           `json:"Status,omitempty"`
+	Url           string           `json:"@odata.id"`
+}
+
+// /redfish/v1/Chassis/X/ThermalSubsystem/Fans/X/
+
+// ThermalFan is the json object for a fan member of the ThermalSubsystem
+type ThermalFan struct {
+	ID           string           `json:"Id"`
+	Name         string           `json:"Name"`
+	Location     PhysicalLocation `json:"Location,omitempty"`
+	SpeedPercent SpeedReading     `json:"SpeedPercent,omitempty"`
+	Status       Status           `json:"Status"`
+	Url          string           `json:"@odata.id"`
+}
+
+// SpeedReading is the json object for a fan speed Sensor excerpt
+type SpeedReading struct {
+	DataSourceUri string      `json:"DataSourceUri,omitempty"`
+	Reading       interface{} `json:"Reading"`
+	SpeedRPM      interface{} `json:"SpeedRPM,omitempty"`
+}
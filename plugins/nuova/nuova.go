@@ -41,6 +41,17 @@ var (
 
 type NuovaPlugin exporter.Exporter
 
+func init() {
+	exporter.RegisterPlugin(exporter.PluginInfo{
+		Name:        "nuova",
+		Description: "Drive metrics of the servers without a RAID controller from the CIMC /nuova XML API",
+		Models:      []string{"cisco"},
+		New: func() exporter.Plugin {
+			return &NuovaPlugin{}
+		},
+	})
+}
+
 func (n *NuovaPlugin) Apply(e *exporter.Exporter) error {
 
 	var handlers []common.Handler